@@ -44,6 +44,21 @@ func (a *AccountLimits) IsUnlimited() bool {
 	return *a == AccountLimits{NoLimit, NoLimit, true, false, NoLimit, NoLimit}
 }
 
+// validateLimit adds an error if the limit is negative but not NoLimit
+func validateLimit(vr *ValidationResults, name string, v int64) {
+	if v < NoLimit {
+		vr.AddError("%s limit %d is invalid, use %d for no limit", name, v, NoLimit)
+	}
+}
+
+// Validate checks that the account limits contain valid values
+func (a *AccountLimits) Validate(vr *ValidationResults) {
+	validateLimit(vr, "imports", a.Imports)
+	validateLimit(vr, "exports", a.Exports)
+	validateLimit(vr, "connections", a.Conn)
+	validateLimit(vr, "leaf node connections", a.LeafNodeConn)
+}
+
 type NatsLimits struct {
 	Subs    int64 `json:"subs,omitempty"`    // Max number of subscriptions
 	Data    int64 `json:"data,omitempty"`    // Max number of bytes
@@ -55,6 +70,16 @@ func (n *NatsLimits) IsUnlimited() bool {
 	return *n == NatsLimits{NoLimit, NoLimit, NoLimit}
 }
 
+// Validate checks that the nats limits contain valid values
+func (n *NatsLimits) Validate(vr *ValidationResults) {
+	validateLimit(vr, "subscriptions", n.Subs)
+	validateLimit(vr, "data", n.Data)
+	validateLimit(vr, "payload", n.Payload)
+	if n.Data > 0 && n.Payload > n.Data {
+		vr.AddWarning("payload limit %d exceeds the data limit %d", n.Payload, n.Data)
+	}
+}
+
 type JetStreamLimits struct {
 	MemoryStorage        int64 `json:"mem_storage,omitempty"`           // Max number of bytes stored in memory across all streams. (0 means disabled)
 	DiskStorage          int64 `json:"disk_storage,omitempty"`          // Max number of bytes stored on disk across all streams. (0 means disabled)
@@ -82,6 +107,48 @@ func (j *JetStreamLimits) IsUnlimited() bool {
 	return lim == JetStreamLimits{NoLimit, NoLimit, NoLimit, NoLimit, 0, 0, 0, false}
 }
 
+// Validate checks that the JetStream limits contain valid values
+func (j *JetStreamLimits) Validate(vr *ValidationResults) {
+	j.validate(vr, "JetStream")
+}
+
+func (j *JetStreamLimits) validate(vr *ValidationResults, kind string) {
+	validateLimit(vr, kind+" memory storage", j.MemoryStorage)
+	validateLimit(vr, kind+" disk storage", j.DiskStorage)
+	validateLimit(vr, kind+" streams", j.Streams)
+	validateLimit(vr, kind+" consumer", j.Consumer)
+	validateLimit(vr, kind+" max ack pending", j.MaxAckPending)
+	validateLimit(vr, kind+" memory max stream bytes", j.MemoryMaxStreamBytes)
+	validateLimit(vr, kind+" disk max stream bytes", j.DiskMaxStreamBytes)
+}
+
+// ValidateStorage warns about per stream maxima not fitting the storage limits and about
+// MaxBytesRequired without per stream maxima. The server accepts such limits, which is
+// why these checks are not part of Validate.
+func (j *JetStreamLimits) ValidateStorage(vr *ValidationResults) {
+	j.validateStorage(vr, "JetStream")
+}
+
+func (j *JetStreamLimits) validateStorage(vr *ValidationResults, kind string) {
+	if j.MemoryMaxStreamBytes > 0 {
+		if j.MemoryStorage == 0 {
+			vr.AddWarning("%s memory max stream bytes %d is set but memory storage is disabled", kind, j.MemoryMaxStreamBytes)
+		} else if j.MemoryStorage > 0 && j.MemoryMaxStreamBytes > j.MemoryStorage {
+			vr.AddWarning("%s memory max stream bytes %d exceeds the memory storage %d", kind, j.MemoryMaxStreamBytes, j.MemoryStorage)
+		}
+	}
+	if j.DiskMaxStreamBytes > 0 {
+		if j.DiskStorage == 0 {
+			vr.AddWarning("%s disk max stream bytes %d is set but disk storage is disabled", kind, j.DiskMaxStreamBytes)
+		} else if j.DiskStorage > 0 && j.DiskMaxStreamBytes > j.DiskStorage {
+			vr.AddWarning("%s disk max stream bytes %d exceeds the disk storage %d", kind, j.DiskMaxStreamBytes, j.DiskStorage)
+		}
+	}
+	if j.MaxBytesRequired && j.MemoryMaxStreamBytes <= 0 && j.DiskMaxStreamBytes <= 0 {
+		vr.AddWarning("%s max bytes required is set without memory or disk max stream bytes", kind)
+	}
+}
+
 type JetStreamTieredLimits map[string]JetStreamLimits
 
 // OperatorLimits are used to limit access by an account
@@ -122,7 +189,10 @@ func (o *OperatorLimits) IsUnlimited() bool {
 
 // Validate checks that the operator limits contain valid values
 func (o *OperatorLimits) Validate(vr *ValidationResults) {
-	// negative values mean unlimited, so all numbers are valid
+	o.NatsLimits.Validate(vr)
+	o.AccountLimits.Validate(vr)
+	o.JetStreamLimits.Validate(vr)
+	o.JetStreamTieredLimits.Validate(vr)
 	if len(o.JetStreamTieredLimits) > 0 {
 		if (o.JetStreamLimits != JetStreamLimits{}) {
 			vr.AddError("JetStream Limits and tiered JetStream Limits are mutually exclusive")
@@ -133,6 +203,12 @@ func (o *OperatorLimits) Validate(vr *ValidationResults) {
 	}
 }
 
+// ValidateStorage checks the flat and tiered JetStream storage limits, see JetStreamLimits.ValidateStorage
+func (o *OperatorLimits) ValidateStorage(vr *ValidationResults) {
+	o.JetStreamLimits.ValidateStorage(vr)
+	o.JetStreamTieredLimits.ValidateStorage(vr)
+}
+
 // WeightedMapping for publishes
 type WeightedMapping struct {
 	Subject Subject `json:"subject"`
@@ -156,6 +232,9 @@ func (m *Mapping) Validate(vr *ValidationResults) {
 		total := uint32(0)
 		for _, e := range wm {
 			e.Subject.Validate(vr)
+			if _, err := NewSubjectTransform(ubFrom, e.Subject); err != nil {
+				vr.AddError("Mapping %q to %q is invalid: %v", ubFrom, e.Subject, err)
+			}
 			if e.GetWeight() > 100 {
 				vr.AddError("Mapping %q has a weight %d that exceeds 100", ubFrom, e.GetWeight())
 			}
@@ -279,7 +358,13 @@ type MsgTrace struct {
 
 // Validate checks if the account is valid, based on the wrapper
 func (a *Account) Validate(acct *AccountClaims, vr *ValidationResults) {
-	a.Imports.Validate(acct.Subject, vr)
+	a.ValidateWithResolver(acct, nil, vr)
+}
+
+// ValidateWithResolver checks if the account is valid, based on the wrapper,
+// using the resolver to look up the activations referenced by the imports
+func (a *Account) ValidateWithResolver(acct *AccountClaims, resolver ActivationResolver, vr *ValidationResults) {
+	a.Imports.ValidateWithResolver(acct.Subject, resolver, vr)
 	a.Exports.Validate(vr)
 	a.Limits.Validate(vr)
 	a.DefaultPermissions.Validate(vr)
@@ -399,8 +484,14 @@ func (a *AccountClaims) Payload() interface{} {
 
 // Validate checks the accounts contents
 func (a *AccountClaims) Validate(vr *ValidationResults) {
+	a.ValidateWithResolver(nil, vr)
+}
+
+// ValidateWithResolver checks the account, using the resolver to look up the activations
+// referenced by the imports. Activations decoded by a caching resolver are reused across calls.
+func (a *AccountClaims) ValidateWithResolver(resolver ActivationResolver, vr *ValidationResults) {
 	a.ClaimsData.Validate(vr)
-	a.Account.Validate(a, vr)
+	a.Account.ValidateWithResolver(a, resolver, vr)
 
 	if nkeys.IsValidPublicAccountKey(a.ClaimsData.Issuer) {
 		if !a.Limits.IsEmpty() {
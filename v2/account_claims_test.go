@@ -1163,3 +1163,113 @@ func TestNewAccountClaimsShouldEnableJetStream(t *testing.T) {
 		})
 	}
 }
+
+func TestOperatorLimits_ValidateValues(t *testing.T) {
+	acc := NewAccountClaims(publicKey(createAccountNKey(t), t))
+	acc.Limits.Subs = -2
+	acc.Limits.Conn = -5
+	acc.Limits.Streams = -3
+	vr := CreateValidationResults()
+	acc.Validate(vr)
+	AssertTrue(hasIssue(vr, true, "subscriptions limit -2 is invalid"), t)
+	AssertTrue(hasIssue(vr, true, "connections limit -5 is invalid"), t)
+	AssertTrue(hasIssue(vr, true, "JetStream streams limit -3 is invalid"), t)
+
+	acc = NewAccountClaims(publicKey(createAccountNKey(t), t))
+	acc.Limits.Data = 100
+	acc.Limits.Payload = 200
+	vr = CreateValidationResults()
+	acc.Validate(vr)
+	AssertTrue(hasIssue(vr, false, "payload limit 200 exceeds the data limit 100"), t)
+	AssertFalse(vr.IsBlocking(true), t)
+}
+
+func TestJetStreamLimits_Validate(t *testing.T) {
+	l := JetStreamLimits{MemoryStorage: 100, DiskStorage: NoLimit, MemoryMaxStreamBytes: 200, DiskMaxStreamBytes: 200}
+	vr := CreateValidationResults()
+	l.Validate(vr)
+	AssertTrue(vr.IsEmpty(), t)
+	l.ValidateStorage(vr)
+	AssertTrue(hasIssue(vr, false, "memory max stream bytes 200 exceeds the memory storage 100"), t)
+	AssertFalse(hasIssue(vr, false, "disk max stream bytes"), t)
+	AssertFalse(vr.IsBlocking(false), t)
+
+	l = JetStreamLimits{MemoryStorage: -2, MemoryMaxStreamBytes: -3}
+	vr = CreateValidationResults()
+	l.Validate(vr)
+	AssertTrue(hasIssue(vr, true, "JetStream memory storage limit -2 is invalid"), t)
+	AssertTrue(hasIssue(vr, true, "JetStream memory max stream bytes limit -3 is invalid"), t)
+
+	l = JetStreamLimits{DiskStorage: 100, MemoryMaxStreamBytes: 50, MaxBytesRequired: true}
+	vr = CreateValidationResults()
+	l.ValidateStorage(vr)
+	AssertTrue(hasIssue(vr, false, "memory max stream bytes 50 is set but memory storage is disabled"), t)
+	AssertFalse(vr.IsBlocking(false), t)
+
+	l = JetStreamLimits{DiskStorage: 100, MaxBytesRequired: true}
+	vr = CreateValidationResults()
+	l.ValidateStorage(vr)
+	AssertTrue(hasIssue(vr, false, "max bytes required is set without memory or disk max stream bytes"), t)
+
+	o := OperatorLimits{JetStreamTieredLimits: JetStreamTieredLimits{
+		"R3": {DiskStorage: 100, DiskMaxStreamBytes: 1000},
+	}}
+	vr = CreateValidationResults()
+	o.ValidateStorage(vr)
+	AssertTrue(hasIssue(vr, false, `JetStream tier "R3" disk max stream bytes 1000 exceeds the disk storage 100`), t)
+
+	// the fixtures of TestJetstreamLimits and TestTieredLimits are reported by ValidateStorage only
+	o = OperatorLimits{JetStreamLimits: JetStreamLimits{MemoryStorage: 3, DiskStorage: 4,
+		MemoryMaxStreamBytes: 1000, DiskMaxStreamBytes: 1000, MaxBytesRequired: true}}
+	vr = CreateValidationResults()
+	o.Validate(vr)
+	AssertTrue(vr.IsEmpty(), t)
+	o.ValidateStorage(vr)
+	AssertTrue(hasIssue(vr, false, "JetStream memory max stream bytes 1000 exceeds the memory storage 3"), t)
+	AssertTrue(hasIssue(vr, false, "JetStream disk max stream bytes 1000 exceeds the disk storage 4"), t)
+	o = OperatorLimits{JetStreamTieredLimits: JetStreamTieredLimits{
+		"R1": {MemoryStorage: 1024, DiskStorage: 1024, Streams: 1, Consumer: 1, MaxBytesRequired: true},
+	}}
+	vr = CreateValidationResults()
+	o.ValidateStorage(vr)
+	AssertTrue(hasIssue(vr, false, `JetStream tier "R1" max bytes required is set without memory or disk max stream bytes`), t)
+}
+
+func TestUserClaims_ValidateLimitsAgainst(t *testing.T) {
+	akp := createAccountNKey(t)
+	acc := NewAccountClaims(publicKey(akp, t))
+	uc := NewUserClaims(publicKey(createUserNKey(t), t))
+	vr := CreateValidationResults()
+	uc.ValidateLimitsAgainst(acc, vr)
+	AssertTrue(vr.IsEmpty(), t)
+
+	acc.Limits.Subs = 10
+	acc.Limits.Payload = 1024
+	uc.Limits.Subs = 20
+	uc.Limits.Payload = 512
+	vr = CreateValidationResults()
+	uc.ValidateLimitsAgainst(acc, vr)
+	AssertTrue(hasIssue(vr, false, "user subscriptions limit 20 exceeds the limit 10"), t)
+	AssertFalse(hasIssue(vr, false, "payload"), t)
+	AssertFalse(hasIssue(vr, false, "data"), t)
+
+	uc.Limits.Subs = NoLimit
+	vr = CreateValidationResults()
+	uc.ValidateLimitsAgainst(acc, vr)
+	AssertTrue(hasIssue(vr, false, "user subscriptions limit is unlimited"), t)
+
+	// scoped users are checked with the limits of the scope
+	skp := createAccountNKey(t)
+	scope := NewUserScope()
+	scope.Key = publicKey(skp, t)
+	scope.Template.Payload = 4096
+	acc.SigningKeys.AddScopedSigner(scope)
+	su := NewUserClaims(publicKey(createUserNKey(t), t))
+	su.SetScoped(true)
+	su.IssuerAccount = acc.Subject
+	su, err := DecodeUserClaims(encode(su, skp, t))
+	AssertNoError(err, t)
+	vr = CreateValidationResults()
+	su.ValidateLimitsAgainst(acc, vr)
+	AssertTrue(hasIssue(vr, false, "user payload limit 4096 exceeds the limit 1024"), t)
+}
@@ -0,0 +1,257 @@
+/*
+ * Copyright 2026 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwt
+
+import (
+	"sort"
+	"strings"
+)
+
+// ImportEdge connects an import of an account with the export it resolves to
+type ImportEdge struct {
+	Importer string
+	Exporter string
+	Import   *Import
+	// Export is the export of the exporting account matching the import, nil if none matches
+	Export *Export
+}
+
+// AccountGraph models all imports and exports between a set of accounts
+type AccountGraph struct {
+	Accounts map[string]*AccountClaims
+	Edges    []*ImportEdge
+	// exports indexes the exports of the accounts by account public key
+	exports map[string]*exportIndex
+	// resolver resolves the activations of imports, nil if tokens are inline
+	resolver ActivationResolver
+}
+
+// NewAccountGraph builds the graph for the provided accounts
+func NewAccountGraph(accounts ...*AccountClaims) *AccountGraph {
+	return NewAccountGraphWithResolver(nil, accounts...)
+}
+
+// NewAccountGraphWithResolver builds the graph for the provided accounts, using the resolver
+// to look up the activations referenced by the imports
+func NewAccountGraphWithResolver(resolver ActivationResolver, accounts ...*AccountClaims) *AccountGraph {
+	g := &AccountGraph{Accounts: make(map[string]*AccountClaims), exports: make(map[string]*exportIndex), resolver: resolver}
+	for _, a := range accounts {
+		if a != nil {
+			g.Accounts[a.Subject] = a
+			g.exports[a.Subject] = newExportIndex(a.Exports)
+		}
+	}
+	for _, k := range g.accountKeys() {
+		a := g.Accounts[k]
+		for _, i := range a.Imports {
+			if i == nil {
+				continue
+			}
+			edge := &ImportEdge{Importer: a.Subject, Exporter: i.Account, Import: i}
+			if exports, ok := g.exports[i.Account]; ok {
+				edge.Export = exports.findImport(i)
+			}
+			g.Edges = append(g.Edges, edge)
+		}
+	}
+	return g
+}
+
+func (g *AccountGraph) accountKeys() []string {
+	keys := make([]string, 0, len(g.Accounts))
+	for k := range g.Accounts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Validate checks the imports of every account against the exports of the other accounts.
+// It returns the validation results keyed by account public key, with an entry for every account.
+func (g *AccountGraph) Validate() map[string]*ValidationResults {
+	report := make(map[string]*ValidationResults, len(g.Accounts))
+	for k := range g.Accounts {
+		report[k] = CreateValidationResults()
+	}
+	for _, e := range g.Edges {
+		g.validateEdge(e, report[e.Importer])
+	}
+	g.validateLocalSubjects(report)
+	g.validateServiceCycles(report)
+	g.validateLatency(report)
+	g.validateTrace(report)
+	return report
+}
+
+func (g *AccountGraph) validateEdge(e *ImportEdge, vr *ValidationResults) {
+	exporter, ok := g.Accounts[e.Exporter]
+	if !ok {
+		vr.AddWarning("import %q is from account %q which is not known", e.Import.Subject, e.Exporter)
+		return
+	}
+	e.Import.validateAgainst(e.Importer, exporter, g.exports[e.Exporter], g.resolver, vr)
+}
+
+func (g *AccountGraph) validateLocalSubjects(report map[string]*ValidationResults) {
+	for _, k := range g.accountKeys() {
+		seen := make(map[string][]string)
+		var order []string
+		for _, i := range g.Accounts[k].Imports {
+			if i == nil {
+				continue
+			}
+			key := i.Type.String() + " " + string(i.localSubject())
+			if _, ok := seen[key]; !ok {
+				order = append(order, key)
+			}
+			seen[key] = append(seen[key], i.Account)
+		}
+		for _, key := range order {
+			if from := seen[key]; len(from) > 1 {
+				kind, subj, _ := strings.Cut(key, " ")
+				report[k].AddWarning("%s local subject %q is imported %d times, from %s",
+					kind, subj, len(from), strings.Join(from, ", "))
+			}
+		}
+	}
+}
+
+// validateServiceCycles follows service imports into the exporting account. A service import
+// continues in the exporting account if that account imports a service under an overlapping subject.
+func (g *AccountGraph) validateServiceCycles(report map[string]*ValidationResults) {
+	var services []*ImportEdge
+	byImporter := make(map[string][]*ImportEdge)
+	for _, e := range g.Edges {
+		if e.Import.IsService() {
+			services = append(services, e)
+			byImporter[e.Importer] = append(byImporter[e.Importer], e)
+		}
+	}
+	next := func(e *ImportEdge) []*ImportEdge {
+		var r []*ImportEdge
+		for _, o := range byImporter[e.Exporter] {
+			if o.Import.localSubject().Intersects(e.Import.remoteSubject()) {
+				r = append(r, o)
+			}
+		}
+		return r
+	}
+	reported := make(map[*ImportEdge]bool)
+	for _, start := range services {
+		if reported[start] {
+			continue
+		}
+		// depth first search for a path leading back to start
+		var path []*ImportEdge
+		visited := make(map[*ImportEdge]bool)
+		var walk func(e *ImportEdge) bool
+		walk = func(e *ImportEdge) bool {
+			path = append(path, e)
+			visited[e] = true
+			for _, n := range next(e) {
+				if n == start {
+					return true
+				}
+				if !visited[n] && walk(n) {
+					return true
+				}
+			}
+			path = path[:len(path)-1]
+			return false
+		}
+		if !walk(start) {
+			continue
+		}
+		accounts := []string{start.Importer}
+		for _, e := range path {
+			accounts = append(accounts, e.Exporter)
+		}
+		cycle := strings.Join(accounts, " -> ")
+		for _, e := range path {
+			reported[e] = true
+			report[e.Importer].AddError("service import %q from account %q is part of an import cycle: %s",
+				e.Import.Subject, e.Exporter, cycle)
+		}
+	}
+}
+
+// validateLatency checks that shared latency is tracked by the exporter and
+// that latency results are not published to exported subjects
+func (g *AccountGraph) validateLatency(report map[string]*ValidationResults) {
+	for _, e := range g.Edges {
+		if e.Export == nil || !e.Import.IsService() || !e.Import.Share {
+			continue
+		}
+		if e.Export.Latency == nil {
+			report[e.Importer].AddWarning("service import %q shares tracking information but account %q does not track latency for %q",
+				e.Import.Subject, e.Exporter, e.Export.Subject)
+		}
+	}
+	for _, k := range g.accountKeys() {
+		a := g.Accounts[k]
+		for _, e := range a.Exports {
+			if e == nil || e.Latency == nil || e.Latency.Results == "" {
+				continue
+			}
+			for _, o := range a.Exports {
+				if o != nil && o.Subject.Intersects(e.Latency.Results) {
+					report[k].AddError("latency results subject %q of export %q collides with %s export %q",
+						e.Latency.Results, e.Subject, o.Type, o.Subject)
+				}
+			}
+		}
+	}
+}
+
+// validateTrace checks that traces crossing an import are enabled on both sides.
+// Traces of service imports flow from the importer to the exporter, which has to allow them on the export.
+// Traces of stream imports flow from the exporter to the importer, which has to allow them on the import.
+func (g *AccountGraph) validateTrace(report map[string]*ValidationResults) {
+	for _, e := range g.Edges {
+		exporter, ok := g.Accounts[e.Exporter]
+		if !ok || e.Export == nil {
+			continue
+		}
+		importer := g.Accounts[e.Importer]
+		if e.Import.IsService() {
+			if e.Export.AllowTrace && importer.Trace == nil {
+				report[e.Importer].AddWarning("account %q allows traces for service %q but the importing account has no trace configuration",
+					e.Exporter, e.Export.Subject)
+			} else if !e.Export.AllowTrace && importer.Trace != nil {
+				report[e.Importer].AddWarning("traces of service import %q stop at account %q which does not allow traces for %q",
+					e.Import.Subject, e.Exporter, e.Export.Subject)
+			}
+		} else if e.Import.IsStream() {
+			if e.Import.AllowTrace && exporter.Trace == nil {
+				report[e.Importer].AddWarning("stream import %q allows traces but account %q has no trace configuration",
+					e.Import.Subject, e.Exporter)
+			} else if !e.Import.AllowTrace && exporter.Trace != nil {
+				report[e.Importer].AddWarning("traces of stream import %q from account %q stop at the import which does not allow traces",
+					e.Import.Subject, e.Exporter)
+			}
+		}
+	}
+	for _, k := range g.accountKeys() {
+		a := g.Accounts[k]
+		if a.Trace == nil || a.Trace.Destination == "" {
+			continue
+		}
+		if !a.DefaultPermissions.Pub.Allows(a.Trace.Destination) {
+			report[k].AddError("the account Trace.Destination subject %q is not publishable with the account default permissions",
+				a.Trace.Destination)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+/*
+ * Copyright 2026 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwt
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func hasIssue(vr *ValidationResults, blocking bool, substr string) bool {
+	for _, i := range vr.Issues {
+		if i.Blocking == blocking && strings.Contains(i.Description, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestAccountGraph_Valid(t *testing.T) {
+	akp := createAccountNKey(t)
+	bkp := createAccountNKey(t)
+	a := NewAccountClaims(publicKey(akp, t))
+	b := NewAccountClaims(publicKey(bkp, t))
+	a.Exports.Add(&Export{Subject: "svc.>", Type: Service}, &Export{Subject: "private", Type: Stream, TokenReq: true})
+
+	act := NewActivationClaims(b.Subject)
+	act.ImportSubject = "private"
+	act.ImportType = Stream
+	b.Imports.Add(&Import{Subject: "svc.a", Account: a.Subject, Type: Service})
+	b.Imports.Add(&Import{Subject: "private", Account: a.Subject, Type: Stream, Token: encode(act, akp, t)})
+
+	g := NewAccountGraph(a, b)
+	AssertEquals(2, len(g.Edges), t)
+	for _, e := range g.Edges {
+		AssertTrue(e.Export != nil, t)
+	}
+	report := g.Validate()
+	AssertEquals(2, len(report), t)
+	AssertTrue(report[a.Subject].IsEmpty(), t)
+	AssertTrue(report[b.Subject].IsEmpty(), t)
+}
+
+func TestAccountGraph_DanglingAndUnknown(t *testing.T) {
+	a := NewAccountClaims(publicKey(createAccountNKey(t), t))
+	b := NewAccountClaims(publicKey(createAccountNKey(t), t))
+	unknown := publicKey(createAccountNKey(t), t)
+	a.Exports.Add(&Export{Subject: "foo", Type: Stream})
+	b.Imports.Add(&Import{Subject: "foo", Account: a.Subject, Type: Service})
+	b.Imports.Add(&Import{Subject: "bar", Account: a.Subject, Type: Stream})
+	b.Imports.Add(&Import{Subject: "baz", Account: unknown, Type: Stream})
+
+	report := NewAccountGraph(a, b).Validate()
+	vr := report[b.Subject]
+	AssertTrue(hasIssue(vr, true, `import "foo" is of type service but account`), t)
+	AssertTrue(hasIssue(vr, true, `import "bar" has no matching stream export`), t)
+	AssertTrue(hasIssue(vr, false, "not known"), t)
+}
+
+func TestAccountGraph_Tokens(t *testing.T) {
+	akp := createAccountNKey(t)
+	a := NewAccountClaims(publicKey(akp, t))
+	b := NewAccountClaims(publicKey(createAccountNKey(t), t))
+	a.Exports.Add(&Export{Subject: "missing", Type: Stream, TokenReq: true})
+	a.Exports.Add(&Export{Subject: "expired", Type: Stream, TokenReq: true})
+	a.Exports.Add(&Export{Subject: "revoked", Type: Stream, TokenReq: true})
+	a.Exports.Add(&Export{Subject: "foreign", Type: Stream, TokenReq: true})
+
+	newToken := func(subj Subject, signer bool) string {
+		act := NewActivationClaims(b.Subject)
+		act.ImportSubject = subj
+		act.ImportType = Stream
+		if subj == "expired" {
+			act.Expires = time.Now().Add(-time.Hour).Unix()
+		}
+		if signer {
+			return encode(act, akp, t)
+		}
+		return encode(act, createAccountNKey(t), t)
+	}
+	b.Imports.Add(&Import{Subject: "missing", Account: a.Subject, Type: Stream})
+	b.Imports.Add(&Import{Subject: "expired", Account: a.Subject, Type: Stream, Token: newToken("expired", true)})
+	b.Imports.Add(&Import{Subject: "revoked", Account: a.Subject, Type: Stream, Token: newToken("revoked", true)})
+	b.Imports.Add(&Import{Subject: "foreign", Account: a.Subject, Type: Stream, Token: newToken("foreign", false)})
+	a.Exports[2].RevokeAt(b.Subject, time.Now().Add(time.Minute))
+
+	vr := NewAccountGraph(a, b).Validate()[b.Subject]
+	AssertTrue(hasIssue(vr, true, `import "missing" requires an activation token`), t)
+	AssertTrue(hasIssue(vr, false, `import "expired" is expired`), t)
+	AssertTrue(hasIssue(vr, true, `import "revoked" is revoked`), t)
+	AssertTrue(hasIssue(vr, true, `import "foreign" is not signed`), t)
+}
+
+func TestAccountGraph_ServiceCycle(t *testing.T) {
+	a := NewAccountClaims(publicKey(createAccountNKey(t), t))
+	b := NewAccountClaims(publicKey(createAccountNKey(t), t))
+	c := NewAccountClaims(publicKey(createAccountNKey(t), t))
+	a.Exports.Add(&Export{Subject: "q", Type: Service})
+	b.Exports.Add(&Export{Subject: "q", Type: Service})
+	c.Exports.Add(&Export{Subject: "other", Type: Service})
+	a.Imports.Add(&Import{Subject: "q", Account: b.Subject, Type: Service})
+	b.Imports.Add(&Import{Subject: "q", Account: a.Subject, Type: Service})
+	c.Imports.Add(&Import{Subject: "q", Account: a.Subject, Type: Service})
+
+	report := NewAccountGraph(a, b, c).Validate()
+	AssertTrue(hasIssue(report[a.Subject], true, "import cycle"), t)
+	AssertTrue(hasIssue(report[b.Subject], true, "import cycle"), t)
+	AssertFalse(hasIssue(report[c.Subject], true, "import cycle"), t)
+}
+
+func TestAccountGraph_DuplicateLocalSubject(t *testing.T) {
+	a := NewAccountClaims(publicKey(createAccountNKey(t), t))
+	b := NewAccountClaims(publicKey(createAccountNKey(t), t))
+	c := NewAccountClaims(publicKey(createAccountNKey(t), t))
+	a.Exports.Add(&Export{Subject: "events", Type: Stream})
+	b.Exports.Add(&Export{Subject: "updates", Type: Stream})
+	c.Imports.Add(&Import{Subject: "events", Account: a.Subject, LocalSubject: "in", Type: Stream})
+	c.Imports.Add(&Import{Subject: "updates", Account: b.Subject, LocalSubject: "in", Type: Stream})
+
+	vr := NewAccountGraph(a, b, c).Validate()[c.Subject]
+	AssertTrue(hasIssue(vr, false, `stream local subject "in" is imported 2 times`), t)
+	AssertFalse(vr.IsBlocking(true), t)
+}
+
+func TestAccountGraph_Latency(t *testing.T) {
+	a := NewAccountClaims(publicKey(createAccountNKey(t), t))
+	b := NewAccountClaims(publicKey(createAccountNKey(t), t))
+	a.Exports.Add(&Export{Subject: "untracked", Type: Service})
+	a.Exports.Add(&Export{Subject: "tracked", Type: Service,
+		Latency: &ServiceLatency{Sampling: 100, Results: "latency.tracked"}})
+	a.Exports.Add(&Export{Subject: "leaky", Type: Service,
+		Latency: &ServiceLatency{Sampling: 100, Results: "events.latency"}})
+	a.Exports.Add(&Export{Subject: "events.>", Type: Stream})
+	b.Imports.Add(&Import{Subject: "untracked", Account: a.Subject, Type: Service, Share: true})
+	b.Imports.Add(&Import{Subject: "tracked", Account: a.Subject, Type: Service, Share: true})
+
+	report := NewAccountGraph(a, b).Validate()
+	vr := report[b.Subject]
+	AssertTrue(hasIssue(vr, false, `service import "untracked" shares tracking information`), t)
+	AssertFalse(hasIssue(vr, false, `service import "tracked"`), t)
+	AssertTrue(hasIssue(report[a.Subject], true, `latency results subject "events.latency" of export "leaky" collides with stream export "events.>"`), t)
+	AssertFalse(hasIssue(report[a.Subject], true, `"latency.tracked"`), t)
+}
+
+func TestAccountGraph_Trace(t *testing.T) {
+	a := NewAccountClaims(publicKey(createAccountNKey(t), t))
+	b := NewAccountClaims(publicKey(createAccountNKey(t), t))
+	a.Exports.Add(&Export{Subject: "svc.allowed", Type: Service, AllowTrace: true})
+	a.Exports.Add(&Export{Subject: "svc.denied", Type: Service})
+	a.Exports.Add(&Export{Subject: "events", Type: Stream})
+	b.Imports.Add(&Import{Subject: "svc.allowed", Account: a.Subject, Type: Service})
+	b.Imports.Add(&Import{Subject: "svc.denied", Account: a.Subject, Type: Service})
+	b.Imports.Add(&Import{Subject: "events", Account: a.Subject, Type: Stream, AllowTrace: true})
+
+	// neither account traces
+	vr := NewAccountGraph(a, b).Validate()[b.Subject]
+	AssertTrue(hasIssue(vr, false, `allows traces for service "svc.allowed" but the importing account has no trace configuration`), t)
+	AssertFalse(hasIssue(vr, false, `"svc.denied"`), t)
+	AssertTrue(hasIssue(vr, false, `stream import "events" allows traces but account`), t)
+
+	// both accounts trace
+	a.Trace = &MsgTrace{Destination: "traces"}
+	b.Trace = &MsgTrace{Destination: "traces"}
+	b.Imports[2].AllowTrace = false
+	vr = NewAccountGraph(a, b).Validate()[b.Subject]
+	AssertFalse(hasIssue(vr, false, `"svc.allowed"`), t)
+	AssertTrue(hasIssue(vr, false, `traces of service import "svc.denied" stop at account`), t)
+	AssertTrue(hasIssue(vr, false, `traces of stream import "events" from account`), t)
+	AssertFalse(vr.IsBlocking(true), t)
+}
+
+func TestAccountGraph_TraceDestination(t *testing.T) {
+	a := NewAccountClaims(publicKey(createAccountNKey(t), t))
+	a.Trace = &MsgTrace{Destination: "traces.a"}
+	AssertTrue(NewAccountGraph(a).Validate()[a.Subject].IsEmpty(), t)
+
+	a.DefaultPermissions.Pub.Deny.Add("traces.>")
+	vr := NewAccountGraph(a).Validate()[a.Subject]
+	AssertTrue(hasIssue(vr, true, `Trace.Destination subject "traces.a" is not publishable`), t)
+
+	a.DefaultPermissions.Pub.Deny = nil
+	a.DefaultPermissions.Pub.Allow.Add("app.>")
+	vr = NewAccountGraph(a).Validate()[a.Subject]
+	AssertTrue(hasIssue(vr, true, `Trace.Destination subject "traces.a" is not publishable`), t)
+}
+
+func BenchmarkAccountGraph_10k(b *testing.B) {
+	exporter := NewAccountClaims("exporter")
+	importer := NewAccountClaims("importer")
+	for i := 0; i < 10000; i++ {
+		exporter.Exports.Add(&Export{Subject: Subject(fmt.Sprintf("svc.%d.*", i)), Type: Service})
+		importer.Imports.Add(&Import{Subject: Subject(fmt.Sprintf("svc.%d.req", i)), Account: "exporter", Type: Service})
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		NewAccountGraph(exporter, importer).Validate()
+	}
+}
@@ -0,0 +1,87 @@
+/*
+ * Copyright 2026 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwt
+
+// AccountUpdateRule checks an account update and adds its issues to vr. The current
+// account is nil if the proposed account is new.
+type AccountUpdateRule func(current *AccountClaims, proposed *AccountClaims, operator *OperatorClaims, vr *ValidationResults)
+
+// CheckAccountUpdate applies the rules the server uses to accept an account JWT replacing current,
+// followed by the extra rules. The proposed account needs to be valid, signed by the operator or one
+// of its signing keys, have the subject of the current account and be issued after it. The system
+// account of the operator can't enable JetStream. Current is nil for a new account.
+func CheckAccountUpdate(current *AccountClaims, proposed *AccountClaims, operator *OperatorClaims, rules ...AccountUpdateRule) *ValidationResults {
+	vr := CreateValidationResults()
+	if proposed == nil {
+		vr.AddError("proposed account is required")
+		return vr
+	}
+	if operator == nil {
+		vr.AddError("operator is required")
+		return vr
+	}
+	proposed.Validate(vr)
+	if !operator.DidSign(proposed) {
+		vr.AddError("account %q is not issued by operator %q or one of its signing keys", proposed.Subject, operator.Subject)
+	}
+	if current != nil {
+		if current.Subject != proposed.Subject {
+			vr.AddError("account subject %q doesn't match the current subject %q", proposed.Subject, current.Subject)
+		}
+		if proposed.IssuedAt <= current.IssuedAt {
+			vr.AddError("account %q is not issued after the current account", proposed.Subject)
+		}
+	}
+	if operator.SystemAccount != "" && proposed.Subject == operator.SystemAccount {
+		if proposed.Limits.IsJSEnabled() {
+			vr.AddError("system account %q can't enable JetStream", proposed.Subject)
+		}
+		if proposed.Limits.Conn == 0 {
+			vr.AddWarning("system account %q doesn't allow connections", proposed.Subject)
+		}
+	}
+	for _, r := range rules {
+		if r != nil {
+			r(current, proposed, operator, vr)
+		}
+	}
+	return vr
+}
+
+// KeepImportedExports returns a rule that refuses to remove or narrow exports still
+// imported by one of the importing accounts
+func KeepImportedExports(importers ...*AccountClaims) AccountUpdateRule {
+	return func(current *AccountClaims, proposed *AccountClaims, _ *OperatorClaims, vr *ValidationResults) {
+		if current == nil {
+			return
+		}
+		before := newExportIndex(current.Exports)
+		after := newExportIndex(proposed.Exports)
+		for _, a := range importers {
+			if a == nil || a.Subject == proposed.Subject {
+				continue
+			}
+			for _, i := range a.Imports {
+				if i == nil || i.Account != proposed.Subject {
+					continue
+				}
+				if before.findImport(i) != nil && after.findImport(i) == nil {
+					vr.AddError("%s import %q of account %q no longer has a matching export", i.Type, i.remoteSubject(), a.Subject)
+				}
+			}
+		}
+	}
+}
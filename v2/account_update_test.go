@@ -0,0 +1,101 @@
+/*
+ * Copyright 2026 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwt
+
+import (
+	"testing"
+)
+
+func TestCheckAccountUpdate(t *testing.T) {
+	okp := createOperatorNKey(t)
+	skp := createOperatorNKey(t)
+	akp := createAccountNKey(t)
+	operator := NewOperatorClaims(publicKey(okp, t))
+	operator.SigningKeys.Add(publicKey(skp, t))
+
+	current, err := DecodeAccountClaims(encode(NewAccountClaims(publicKey(akp, t)), okp, t))
+	AssertNoError(err, t)
+	proposed, err := DecodeAccountClaims(encode(NewAccountClaims(publicKey(akp, t)), skp, t))
+	AssertNoError(err, t)
+	proposed.IssuedAt = current.IssuedAt + 1
+
+	vr := CheckAccountUpdate(current, proposed, operator)
+	AssertTrue(vr.IsEmpty(), t)
+	AssertTrue(CheckAccountUpdate(nil, proposed, operator).IsEmpty(), t)
+
+	proposed.IssuedAt = current.IssuedAt
+	vr = CheckAccountUpdate(current, proposed, operator)
+	AssertTrue(hasIssue(vr, true, "is not issued after the current account"), t)
+
+	other, err := DecodeAccountClaims(encode(NewAccountClaims(publicKey(createAccountNKey(t), t)), createOperatorNKey(t), t))
+	AssertNoError(err, t)
+	other.IssuedAt = current.IssuedAt + 1
+	vr = CheckAccountUpdate(current, other, operator)
+	AssertTrue(hasIssue(vr, true, "doesn't match the current subject"), t)
+	AssertTrue(hasIssue(vr, true, "is not issued by operator"), t)
+
+	AssertTrue(CheckAccountUpdate(current, nil, operator).IsBlocking(false), t)
+	AssertTrue(CheckAccountUpdate(current, proposed, nil).IsBlocking(false), t)
+}
+
+func TestCheckAccountUpdate_SystemAccount(t *testing.T) {
+	okp := createOperatorNKey(t)
+	akp := createAccountNKey(t)
+	operator := NewOperatorClaims(publicKey(okp, t))
+	operator.SystemAccount = publicKey(akp, t)
+
+	sys := NewAccountClaims(publicKey(akp, t))
+	sys.Limits.JetStreamLimits.DiskStorage = 1024
+	sys.Limits.Conn = 0
+	proposed, err := DecodeAccountClaims(encode(sys, okp, t))
+	AssertNoError(err, t)
+	vr := CheckAccountUpdate(nil, proposed, operator)
+	AssertTrue(hasIssue(vr, true, "can't enable JetStream"), t)
+	AssertTrue(hasIssue(vr, false, "doesn't allow connections"), t)
+}
+
+func TestCheckAccountUpdate_Rules(t *testing.T) {
+	okp := createOperatorNKey(t)
+	akp := createAccountNKey(t)
+	bkp := createAccountNKey(t)
+	operator := NewOperatorClaims(publicKey(okp, t))
+
+	a := NewAccountClaims(publicKey(akp, t))
+	a.Exports.Add(&Export{Subject: "svc.>", Type: Service}, &Export{Subject: "events.>", Type: Stream})
+	current, err := DecodeAccountClaims(encode(a, okp, t))
+	AssertNoError(err, t)
+
+	b := NewAccountClaims(publicKey(bkp, t))
+	b.Imports.Add(&Import{Subject: "svc.q", Account: a.Subject, Type: Service})
+
+	// narrowing the service export drops the import of svc.q, removing the stream isn't imported
+	a.Exports = Exports{{Subject: "svc.other", Type: Service}}
+	proposed, err := DecodeAccountClaims(encode(a, okp, t))
+	AssertNoError(err, t)
+	proposed.IssuedAt = current.IssuedAt + 1
+
+	AssertTrue(CheckAccountUpdate(current, proposed, operator).IsEmpty(), t)
+	vr := CheckAccountUpdate(current, proposed, operator, KeepImportedExports(b))
+	AssertEquals(1, len(vr.Issues), t)
+	AssertTrue(hasIssue(vr, true, `service import "svc.q" of account "`+b.Subject+`"`), t)
+
+	called := false
+	custom := func(c *AccountClaims, p *AccountClaims, o *OperatorClaims, vr *ValidationResults) {
+		called = c == current && p == proposed && o == operator
+	}
+	CheckAccountUpdate(current, proposed, operator, nil, custom)
+	AssertTrue(called, t)
+}
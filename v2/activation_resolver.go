@@ -0,0 +1,175 @@
+/*
+ * Copyright 2026 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// ActivationResolver resolves the value of Import.Token to the decoded activation.
+// The value is either an inline activation JWT or a reference, such as the activation's HashID.
+// Implementations may cache and share the returned claims, callers should not modify them.
+type ActivationResolver interface {
+	Resolve(ref string) (*ActivationClaims, error)
+}
+
+// isInlineToken returns true if the value looks like a JWT rather than a reference
+func isInlineToken(ref string) bool {
+	return strings.Count(ref, ".") == 2
+}
+
+// activationCache caches decoded activations by token, so tokens are decoded only once
+type activationCache struct {
+	mu      sync.Mutex
+	decoded map[string]*ActivationClaims
+}
+
+func (c *activationCache) decode(token string) (*ActivationClaims, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if act, ok := c.decoded[token]; ok {
+		return act, nil
+	}
+	act, err := DecodeActivationClaims(token)
+	if err != nil {
+		return nil, err
+	}
+	if c.decoded == nil {
+		c.decoded = make(map[string]*ActivationClaims)
+	}
+	c.decoded[token] = act
+	return act, nil
+}
+
+// forget drops the decoded activation of a token that is no longer referenced
+func (c *activationCache) forget(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.decoded, token)
+}
+
+// MemActivationResolver resolves activations stored in memory by their HashID.
+// Inline tokens are decoded as well. Decoded activations are cached.
+type MemActivationResolver struct {
+	activationCache
+	tokensMu sync.Mutex
+	tokens   map[string]string
+}
+
+// NewMemActivationResolver creates an empty in-memory resolver
+func NewMemActivationResolver() *MemActivationResolver {
+	return &MemActivationResolver{tokens: make(map[string]string)}
+}
+
+// Store adds the activation token and returns its HashID, usable as Import.Token
+func (r *MemActivationResolver) Store(token string) (string, error) {
+	act, err := r.decode(token)
+	if err != nil {
+		return "", err
+	}
+	id, err := act.HashID()
+	if err != nil {
+		return "", err
+	}
+	r.tokensMu.Lock()
+	defer r.tokensMu.Unlock()
+	r.tokens[id] = token
+	return id, nil
+}
+
+// Resolve returns the activation for an inline token or a stored HashID
+func (r *MemActivationResolver) Resolve(ref string) (*ActivationClaims, error) {
+	if isInlineToken(ref) {
+		return r.decode(ref)
+	}
+	r.tokensMu.Lock()
+	token, ok := r.tokens[ref]
+	r.tokensMu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("activation %q not found", ref)
+	}
+	return r.decode(token)
+}
+
+// DirActivationResolver resolves activations stored as <HashID>.jwt files in a directory.
+// Inline tokens are decoded as well. Decoded activations are cached.
+type DirActivationResolver struct {
+	activationCache
+	dir string
+}
+
+// NewDirActivationResolver creates a resolver for an existing directory
+func NewDirActivationResolver(dir string) (*DirActivationResolver, error) {
+	fi, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !fi.IsDir() {
+		return nil, fmt.Errorf("%q is not a directory", dir)
+	}
+	return &DirActivationResolver{dir: dir}, nil
+}
+
+func (r *DirActivationResolver) path(ref string) (string, error) {
+	if ref == "" || filepath.Base(ref) != ref || strings.ContainsAny(ref, `/\`) {
+		return "", fmt.Errorf("invalid activation reference %q", ref)
+	}
+	return filepath.Join(r.dir, ref+".jwt"), nil
+}
+
+// Store writes the activation token to the directory and returns its HashID, usable as Import.Token
+func (r *DirActivationResolver) Store(token string) (string, error) {
+	act, err := r.decode(token)
+	if err != nil {
+		return "", err
+	}
+	id, err := act.HashID()
+	if err != nil {
+		return "", err
+	}
+	fp, err := r.path(id)
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(fp, []byte(token), 0600); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// Resolve returns the activation for an inline token or a HashID stored in the directory
+func (r *DirActivationResolver) Resolve(ref string) (*ActivationClaims, error) {
+	if isInlineToken(ref) {
+		return r.decode(ref)
+	}
+	fp, err := r.path(ref)
+	if err != nil {
+		return nil, err
+	}
+	d, err := os.ReadFile(fp)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("activation %q not found", ref)
+		}
+		return nil, err
+	}
+	return r.decode(strings.TrimSpace(string(d)))
+}
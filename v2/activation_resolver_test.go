@@ -0,0 +1,158 @@
+/*
+ * Copyright 2026 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwt
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMemActivationResolver(t *testing.T) {
+	akp := createAccountNKey(t)
+	act := NewActivationClaims(publicKey(createAccountNKey(t), t))
+	act.ImportSubject = "foo"
+	act.ImportType = Stream
+	token := encode(act, akp, t)
+
+	r := NewMemActivationResolver()
+	id, err := r.Store(token)
+	AssertNoError(err, t)
+	hid, err := act.HashID()
+	AssertNoError(err, t)
+	AssertEquals(hid, id, t)
+
+	a1, err := r.Resolve(id)
+	AssertNoError(err, t)
+	AssertEquals(Subject("foo"), a1.ImportSubject, t)
+	a2, err := r.Resolve(token)
+	AssertNoError(err, t)
+	// decoded activations are cached
+	AssertTrue(a1 == a2, t)
+
+	_, err = r.Resolve("unknown")
+	AssertTrue(err != nil, t)
+	_, err = r.Store("not.a.token")
+	AssertTrue(err != nil, t)
+}
+
+func TestDirActivationResolver(t *testing.T) {
+	dir := t.TempDir()
+	r, err := NewDirActivationResolver(dir)
+	AssertNoError(err, t)
+
+	akp := createAccountNKey(t)
+	act := NewActivationClaims(publicKey(createAccountNKey(t), t))
+	act.ImportSubject = "foo"
+	act.ImportType = Stream
+	token := encode(act, akp, t)
+	id, err := r.Store(token)
+	AssertNoError(err, t)
+	_, err = os.Stat(filepath.Join(dir, id+".jwt"))
+	AssertNoError(err, t)
+
+	// a fresh resolver reads the stored file
+	r2, err := NewDirActivationResolver(dir)
+	AssertNoError(err, t)
+	a, err := r2.Resolve(id)
+	AssertNoError(err, t)
+	AssertEquals(Subject("foo"), a.ImportSubject, t)
+
+	_, err = r2.Resolve("unknown")
+	AssertTrue(err != nil, t)
+	_, err = r2.Resolve("../secret")
+	AssertTrue(err != nil, t)
+
+	_, err = NewDirActivationResolver(filepath.Join(dir, id+".jwt"))
+	AssertTrue(err != nil, t)
+	_, err = NewDirActivationResolver(filepath.Join(dir, "missing"))
+	AssertTrue(err != nil, t)
+}
+
+func TestImport_ValidateWithResolver(t *testing.T) {
+	akp := createAccountNKey(t)
+	bkp := createAccountNKey(t)
+	act := NewActivationClaims(publicKey(bkp, t))
+	act.ImportSubject = "foo"
+	act.ImportType = Stream
+	token := encode(act, akp, t)
+
+	r := NewMemActivationResolver()
+	id, err := r.Store(token)
+	AssertNoError(err, t)
+
+	i := &Import{Subject: "foo", Account: publicKey(akp, t), Type: Stream, Token: id}
+	vr := CreateValidationResults()
+	i.ValidateWithResolver(publicKey(bkp, t), r, vr)
+	AssertTrue(vr.IsEmpty(), t)
+
+	// without a resolver the reference is not a valid token
+	vr = CreateValidationResults()
+	i.Validate(publicKey(bkp, t), vr)
+	AssertTrue(vr.IsBlocking(false), t)
+
+	imports := Imports{i}
+	vr = CreateValidationResults()
+	imports.ValidateWithResolver(publicKey(bkp, t), r, vr)
+	AssertTrue(vr.IsEmpty(), t)
+}
+
+func TestActivationResolver_AccountValidation(t *testing.T) {
+	ekp := createAccountNKey(t)
+	ikp := createAccountNKey(t)
+	exporter := NewAccountClaims(publicKey(ekp, t))
+	e := &Export{Subject: "foo", Type: Stream, TokenReq: true}
+	exporter.Exports.Add(e)
+	importer := NewAccountClaims(publicKey(ikp, t))
+
+	token, err := e.IssueActivation(exporter, importer.Subject, ekp, &ActivationOptions{Expiry: time.Minute})
+	AssertNoError(err, t)
+	r := NewMemActivationResolver()
+	id, err := r.Store(token)
+	AssertNoError(err, t)
+	importer.Imports.Add(&Import{Subject: "foo", Account: exporter.Subject, Type: Stream, Token: id})
+
+	// a reference can't be decoded without the resolver
+	vr := CreateValidationResults()
+	importer.Validate(vr)
+	AssertTrue(vr.IsBlocking(false), t)
+	AssertTrue(NewAccountGraph(exporter, importer).Validate()[importer.Subject].IsBlocking(false), t)
+
+	vr = CreateValidationResults()
+	importer.ValidateWithResolver(r, vr)
+	AssertTrue(vr.IsEmpty(), t)
+	AssertTrue(NewAccountGraphWithResolver(r, exporter, importer).Validate()[importer.Subject].IsEmpty(), t)
+	vr = CreateValidationResults()
+	importer.Imports[0].ValidateAgainstWithResolver(importer.Subject, exporter, r, vr)
+	AssertTrue(vr.IsEmpty(), t)
+
+	scan := ScanImportActivations(importer, time.Hour)
+	AssertEquals(1, len(scan), t)
+	AssertTrue(scan[0].Err != nil, t)
+	scan = ScanImportActivationsWithResolver(importer, time.Hour, r)
+	AssertEquals(1, len(scan), t)
+	AssertNoError(scan[0].Err, t)
+
+	signer := func(_ string, act *ActivationClaims) (string, error) {
+		return act.Encode(ekp)
+	}
+	renewed, report, err := RenewImportActivationsWithResolver(importer, time.Hour, signer, r)
+	AssertNoError(err, t)
+	AssertEquals(1, len(report), t)
+	AssertNoError(report[0].Err, t)
+	AssertTrue(renewed.Imports[0].Token != id, t)
+}
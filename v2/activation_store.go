@@ -0,0 +1,237 @@
+/*
+ * Copyright 2026 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// StoredActivation is an activation token held by an ActivationStore
+type StoredActivation struct {
+	// ID is the HashID of the activation
+	ID         string
+	Token      string
+	Activation *ActivationClaims
+}
+
+// Exporter returns the public key of the exporting account, which is the issuer account
+// if the activation was signed with a signing key
+func (s *StoredActivation) Exporter() string {
+	if s.Activation.IssuerAccount != "" {
+		return s.Activation.IssuerAccount
+	}
+	return s.Activation.Issuer
+}
+
+// Importer returns the public key of the importing account
+func (s *StoredActivation) Importer() string {
+	return s.Activation.Subject
+}
+
+// ActivationStore holds activation tokens keyed by their HashID. Revocations are read from
+// the Export.Revocations of exporting accounts registered with UpdateExporter.
+// The store extends MemActivationResolver, is safe for concurrent use and implements ActivationResolver.
+type ActivationStore struct {
+	MemActivationResolver
+	mu        sync.Mutex
+	exporters map[string]*AccountClaims
+}
+
+// NewActivationStore creates an empty activation store
+func NewActivationStore() *ActivationStore {
+	return &ActivationStore{
+		MemActivationResolver: MemActivationResolver{tokens: make(map[string]string)},
+		exporters:             make(map[string]*AccountClaims),
+	}
+}
+
+// Add decodes and stores the activation token and returns its HashID. A token with the
+// same HashID is replaced, unless it was issued later than the one added.
+func (s *ActivationStore) Add(token string) (string, error) {
+	act, err := s.decode(token)
+	if err != nil {
+		return "", err
+	}
+	vr := CreateValidationResults()
+	act.validateWithTimeChecks(vr, false)
+	if errs := vr.Errors(); len(errs) > 0 {
+		s.forget(token)
+		return "", errs[0]
+	}
+	id, err := act.HashID()
+	if err != nil {
+		return "", err
+	}
+	s.tokensMu.Lock()
+	defer s.tokensMu.Unlock()
+	if cur, ok := s.tokens[id]; ok && cur != token {
+		if prev, err := s.decode(cur); err == nil && prev.IssuedAt > act.IssuedAt {
+			s.forget(token)
+			return id, nil
+		}
+		s.forget(cur)
+	}
+	s.tokens[id] = token
+	return id, nil
+}
+
+// Store is Add, so tokens stored through the resolver are validated as well
+func (s *ActivationStore) Store(token string) (string, error) {
+	return s.Add(token)
+}
+
+// stored returns the activation for a stored token
+func (s *ActivationStore) stored(id string, token string) *StoredActivation {
+	act, err := s.decode(token)
+	if err != nil {
+		return nil
+	}
+	return &StoredActivation{ID: id, Token: token, Activation: act}
+}
+
+// Get returns the activation stored under the HashID
+func (s *ActivationStore) Get(id string) (*StoredActivation, bool) {
+	s.tokensMu.Lock()
+	token, ok := s.tokens[id]
+	s.tokensMu.Unlock()
+	if !ok {
+		return nil, false
+	}
+	sa := s.stored(id, token)
+	return sa, sa != nil
+}
+
+// Remove deletes the activation stored under the HashID, returning true if it was present
+func (s *ActivationStore) Remove(id string) bool {
+	s.tokensMu.Lock()
+	defer s.tokensMu.Unlock()
+	token, ok := s.tokens[id]
+	if ok {
+		delete(s.tokens, id)
+		s.forget(token)
+	}
+	return ok
+}
+
+// Len returns the number of stored activations
+func (s *ActivationStore) Len() int {
+	s.tokensMu.Lock()
+	defer s.tokensMu.Unlock()
+	return len(s.tokens)
+}
+
+// list returns the stored activations accepted by fn, sorted by ID
+func (s *ActivationStore) list(fn func(sa *StoredActivation) bool) []*StoredActivation {
+	s.tokensMu.Lock()
+	tokens := make(map[string]string, len(s.tokens))
+	for id, token := range s.tokens {
+		tokens[id] = token
+	}
+	s.tokensMu.Unlock()
+	var r []*StoredActivation
+	for id, token := range tokens {
+		if sa := s.stored(id, token); sa != nil && fn(sa) {
+			r = append(r, sa)
+		}
+	}
+	sort.Slice(r, func(i, j int) bool {
+		return r[i].ID < r[j].ID
+	})
+	return r
+}
+
+// ByExporter returns the activations issued by the exporting account
+func (s *ActivationStore) ByExporter(account string) []*StoredActivation {
+	return s.list(func(sa *StoredActivation) bool {
+		return sa.Exporter() == account
+	})
+}
+
+// ByImporter returns the activations issued to the importing account
+func (s *ActivationStore) ByImporter(account string) []*StoredActivation {
+	return s.list(func(sa *StoredActivation) bool {
+		return sa.Importer() == account
+	})
+}
+
+// Expire removes the activations expired at the provided time and returns them
+func (s *ActivationStore) Expire(now time.Time) []*StoredActivation {
+	expired := s.list(func(sa *StoredActivation) bool {
+		return sa.Activation.Expires > 0 && sa.Activation.Expires <= now.Unix()
+	})
+	s.tokensMu.Lock()
+	defer s.tokensMu.Unlock()
+	for _, sa := range expired {
+		// keep a token renewed in the meantime
+		if s.tokens[sa.ID] == sa.Token {
+			delete(s.tokens, sa.ID)
+			s.forget(sa.Token)
+		}
+	}
+	return expired
+}
+
+// UpdateExporter registers the current claims of an exporting account. Its exports
+// are used to look up revocations of the activations it issued.
+func (s *ActivationStore) UpdateExporter(exporter *AccountClaims) error {
+	if exporter == nil || exporter.Subject == "" {
+		return errors.New("exporter account is required")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.exporters[exporter.Subject] = exporter
+	return nil
+}
+
+// export returns the export of the registered exporting account the activation applies to
+func (s *ActivationStore) export(sa *StoredActivation) *Export {
+	s.mu.Lock()
+	exporter, ok := s.exporters[sa.Exporter()]
+	s.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	return newExportIndex(exporter.Exports).find(sa.Activation.ImportSubject, sa.Activation.ImportType)
+}
+
+// IsRevoked returns true if the export the activation applies to revoked it.
+// Activations of exporters that are not registered are not considered revoked.
+func (s *ActivationStore) IsRevoked(id string) (bool, error) {
+	sa, ok := s.Get(id)
+	if !ok {
+		return false, fmt.Errorf("activation %q not found", id)
+	}
+	e := s.export(sa)
+	if e == nil {
+		return false, nil
+	}
+	return e.IsClaimRevoked(sa.Activation), nil
+}
+
+// Revoked returns the stored activations revoked by their exporting account
+func (s *ActivationStore) Revoked() []*StoredActivation {
+	var r []*StoredActivation
+	for _, sa := range s.list(func(*StoredActivation) bool { return true }) {
+		if e := s.export(sa); e != nil && e.IsClaimRevoked(sa.Activation) {
+			r = append(r, sa)
+		}
+	}
+	return r
+}
@@ -0,0 +1,123 @@
+/*
+ * Copyright 2026 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwt
+
+import (
+	"testing"
+	"time"
+)
+
+func TestActivationStore(t *testing.T) {
+	akp := createAccountNKey(t)
+	skp := createAccountNKey(t)
+	exporter := NewAccountClaims(publicKey(akp, t))
+	exporter.SigningKeys.Add(publicKey(skp, t))
+	exporter.Exports.Add(&Export{Subject: "a.>", Type: Stream, TokenReq: true})
+	exporter.Exports.Add(&Export{Subject: "b", Type: Service, TokenReq: true})
+	b := publicKey(createAccountNKey(t), t)
+	c := publicKey(createAccountNKey(t), t)
+
+	newToken := func(importer string, subj Subject, typ ExportType, expires int64) string {
+		act := NewActivationClaims(importer)
+		act.ImportSubject = subj
+		act.ImportType = typ
+		act.Expires = expires
+		act.IssuerAccount = exporter.Subject
+		return encode(act, skp, t)
+	}
+
+	s := NewActivationStore()
+	id1, err := s.Add(newToken(b, "a.b", Stream, 0))
+	AssertNoError(err, t)
+	id2, err := s.Add(newToken(c, "a.c", Stream, 0))
+	AssertNoError(err, t)
+	id3, err := s.Add(newToken(b, "b", Service, time.Now().Add(-time.Minute).Unix()))
+	AssertNoError(err, t)
+	AssertEquals(3, s.Len(), t)
+	_, err = s.Add("bad")
+	AssertTrue(err != nil, t)
+
+	sa, ok := s.Get(id1)
+	AssertTrue(ok, t)
+	AssertEquals(exporter.Subject, sa.Exporter(), t)
+	AssertEquals(b, sa.Importer(), t)
+	AssertEquals(3, len(s.ByExporter(exporter.Subject)), t)
+	AssertEquals(2, len(s.ByImporter(b)), t)
+	AssertEquals(1, len(s.ByImporter(c)), t)
+
+	act, err := s.Resolve(id2)
+	AssertNoError(err, t)
+	AssertEquals(Subject("a.c"), act.ImportSubject, t)
+
+	// without the exporter no revocations are known
+	revoked, err := s.IsRevoked(id1)
+	AssertNoError(err, t)
+	AssertFalse(revoked, t)
+
+	exporter.Exports[0].RevokeAt(b, time.Now().Add(time.Minute))
+	AssertNoError(s.UpdateExporter(exporter), t)
+	revoked, err = s.IsRevoked(id1)
+	AssertNoError(err, t)
+	AssertTrue(revoked, t)
+	revoked, err = s.IsRevoked(id2)
+	AssertNoError(err, t)
+	AssertFalse(revoked, t)
+	r := s.Revoked()
+	AssertEquals(1, len(r), t)
+	AssertEquals(id1, r[0].ID, t)
+
+	expired := s.Expire(time.Now())
+	AssertEquals(1, len(expired), t)
+	AssertEquals(id3, expired[0].ID, t)
+	_, ok = s.Get(id3)
+	AssertFalse(ok, t)
+
+	AssertTrue(s.Remove(id2), t)
+	AssertFalse(s.Remove(id2), t)
+	_, err = s.IsRevoked(id2)
+	AssertTrue(err != nil, t)
+	AssertEquals(1, s.Len(), t)
+}
+
+func TestActivationStore_ImportValidation(t *testing.T) {
+	akp := createAccountNKey(t)
+	bkp := createAccountNKey(t)
+	act := NewActivationClaims(publicKey(bkp, t))
+	act.ImportSubject = "foo"
+	act.ImportType = Stream
+
+	s := NewActivationStore()
+	id, err := s.Add(encode(act, akp, t))
+	AssertNoError(err, t)
+	i := &Import{Subject: "foo", Account: publicKey(akp, t), Type: Stream, Token: id}
+	vr := CreateValidationResults()
+	i.ValidateWithResolver(publicKey(bkp, t), s, vr)
+	AssertTrue(vr.IsEmpty(), t)
+
+	// the store shares the decode cache of the resolver it extends
+	token := encode(act, akp, t)
+	id2, err := s.Store(token)
+	AssertNoError(err, t)
+	AssertEquals(id, id2, t)
+	a1, err := s.Resolve(id)
+	AssertNoError(err, t)
+	a2, err := s.Resolve(token)
+	AssertNoError(err, t)
+	AssertTrue(a1 == a2, t)
+	AssertTrue(s.Remove(id), t)
+	_, err = s.Resolve(id)
+	AssertTrue(err != nil, t)
+}
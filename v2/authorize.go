@@ -0,0 +1,193 @@
+/*
+ * Copyright 2026 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwt
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// DenyCode identifies why a connection is not authorized
+type DenyCode string
+
+const (
+	DenyExpired          DenyCode = "expired"
+	DenyNotYetValid      DenyCode = "not_yet_valid"
+	DenyRevoked          DenyCode = "revoked"
+	DenyBearerDisallowed DenyCode = "bearer_disallowed"
+	DenyNonceRequired    DenyCode = "nonce_required"
+	DenyTimeWindow       DenyCode = "time_window"
+	DenySourceIP         DenyCode = "source_ip"
+	DenyConnectionType   DenyCode = "connection_type"
+	DenyIssuer           DenyCode = "issuer"
+	DenyMissingClaims    DenyCode = "missing_claims"
+)
+
+// DenyReason describes why a connection is not authorized
+type DenyReason struct {
+	Code        DenyCode
+	Description string
+}
+
+func (r DenyReason) String() string {
+	return fmt.Sprintf("%s: %s", r.Code, r.Description)
+}
+
+// ConnContext describes a connection attempt
+type ConnContext struct {
+	// SourceIP is the address the client connects from
+	SourceIP net.IP
+	// ConnectionType is one of the ConnectionType constants, STANDARD if not set
+	ConnectionType string
+	// Time is the time of the connection attempt, now if not set
+	Time time.Time
+	// TLS is the state of the TLS connection, nil for plain connections. It is not
+	// evaluated, but kept with the decision for reporting.
+	TLS *tls.ConnectionState
+	// NonceSigned is true if the client signed the nonce sent by the server
+	NonceSigned bool
+}
+
+// AuthorizationDecision is the outcome of Authorize
+type AuthorizationDecision struct {
+	Allowed bool
+	// Reasons lists why the connection is denied, in the order the checks are made
+	Reasons []DenyReason
+	// Limits are the effective permissions and limits of the user, taken from the
+	// template of the scoped signing key that issued the user, if any
+	Limits  *UserPermissionLimits
+	Context ConnContext
+}
+
+// Denied returns true if the decision contains a reason with the code
+func (d *AuthorizationDecision) Denied(code DenyCode) bool {
+	for _, r := range d.Reasons {
+		if r.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *AuthorizationDecision) String() string {
+	if d.Allowed {
+		return "allowed"
+	}
+	r := make([]string, len(d.Reasons))
+	for i, v := range d.Reasons {
+		r[i] = v.String()
+	}
+	return "denied: " + strings.Join(r, "; ")
+}
+
+func (d *AuthorizationDecision) deny(code DenyCode, format string, args ...interface{}) {
+	d.Reasons = append(d.Reasons, DenyReason{Code: code, Description: fmt.Sprintf(format, args...)})
+}
+
+// claimTimes adds the expiration and not before reasons of a claim
+func (d *AuthorizationDecision) claimTimes(kind string, c *ClaimsData, now int64) {
+	if c.Expires > 0 && now > c.Expires {
+		d.deny(DenyExpired, "%s %q expired at %s", kind, c.Subject, time.Unix(c.Expires, 0).UTC().Format(time.RFC3339))
+	}
+	if c.NotBefore > 0 && c.NotBefore > now {
+		d.deny(DenyNotYetValid, "%s %q is not valid before %s", kind, c.Subject, time.Unix(c.NotBefore, 0).UTC().Format(time.RFC3339))
+	}
+}
+
+// userScope returns the user scope of the signing key that issued the user, nil if the key is not scoped
+func userScope(account *AccountClaims, user *UserClaims) *UserScope {
+	scope, ok := account.SigningKeys.GetScope(user.Issuer)
+	if !ok || scope == nil {
+		return nil
+	}
+	switch s := scope.(type) {
+	case *UserScope:
+		return s
+	case UserScope:
+		return &s
+	}
+	return nil
+}
+
+// Authorize decides offline if the user can connect as the server would. The operator is optional,
+// if set the account needs to be issued by it. The decision lists every reason to deny the connection,
+// ordered as: expired, not yet valid, revoked, bearer token disallowed, nonce not signed, outside time window,
+// source ip not allowed, connection type not allowed and wrong issuer.
+func Authorize(operator *OperatorClaims, account *AccountClaims, user *UserClaims, ctx ConnContext) *AuthorizationDecision {
+	if ctx.Time.IsZero() {
+		ctx.Time = time.Now()
+	}
+	if ctx.ConnectionType == "" {
+		ctx.ConnectionType = ConnectionTypeStandard
+	}
+	d := &AuthorizationDecision{Context: ctx}
+	if account == nil || user == nil {
+		d.deny(DenyMissingClaims, "account and user claims are required")
+		return d
+	}
+	limits := user.UserPermissionLimits
+	scope := userScope(account, user)
+	if scope != nil {
+		limits = scope.Template
+	}
+	d.Limits = &limits
+
+	now := ctx.Time.Unix()
+	d.claimTimes("user", &user.ClaimsData, now)
+	d.claimTimes("account", &account.ClaimsData, now)
+	if operator != nil {
+		d.claimTimes("operator", &operator.ClaimsData, now)
+	}
+
+	if account.IsClaimRevoked(user) {
+		d.deny(DenyRevoked, "user %q is revoked by account %q", user.Subject, account.Subject)
+	}
+
+	if limits.BearerToken {
+		if account.Limits.DisallowBearer {
+			d.deny(DenyBearerDisallowed, "user %q is a bearer token, which account %q disallows", user.Subject, account.Subject)
+		}
+	} else if !ctx.NonceSigned {
+		d.deny(DenyNonceRequired, "user %q is not a bearer token and the nonce was not signed", user.Subject)
+	}
+
+	if !limits.AllowsAt(ctx.Time) {
+		d.deny(DenyTimeWindow, "connection at %s is outside the time windows of user %q", ctx.Time.Format(time.RFC3339), user.Subject)
+	}
+	if !limits.AllowsSource(ctx.SourceIP) {
+		d.deny(DenySourceIP, "source ip %s is not allowed for user %q", ctx.SourceIP, user.Subject)
+	}
+	if !limits.AllowsConnectionType(ctx.ConnectionType) {
+		d.deny(DenyConnectionType, "connection type %q is not allowed for user %q", ctx.ConnectionType, user.Subject)
+	}
+
+	if !account.DidSign(user) {
+		d.deny(DenyIssuer, "user %q is not issued by account %q or one of its signing keys", user.Subject, account.Subject)
+	} else if scope != nil {
+		if err := scope.ValidateScopedSigner(user); err != nil {
+			d.deny(DenyIssuer, "user %q issued by scoped signing key %q: %v", user.Subject, user.Issuer, err)
+		}
+	}
+	if operator != nil && !operator.DidSign(account) {
+		d.deny(DenyIssuer, "account %q is not issued by operator %q or one of its signing keys", account.Subject, operator.Subject)
+	}
+
+	d.Allowed = len(d.Reasons) == 0
+	return d
+}
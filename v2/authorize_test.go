@@ -0,0 +1,138 @@
+/*
+ * Copyright 2026 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwt
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+type authorizeFixture struct {
+	operator *OperatorClaims
+	account  *AccountClaims
+	user     *UserClaims
+}
+
+func newAuthorizeFixture(t *testing.T) *authorizeFixture {
+	okp := createOperatorNKey(t)
+	akp := createAccountNKey(t)
+	ukp := createUserNKey(t)
+	f := &authorizeFixture{}
+	f.operator = NewOperatorClaims(publicKey(okp, t))
+	f.account = NewAccountClaims(publicKey(akp, t))
+	var err error
+	f.account, err = DecodeAccountClaims(encode(f.account, okp, t))
+	AssertNoError(err, t)
+	f.user, err = DecodeUserClaims(encode(NewUserClaims(publicKey(ukp, t)), akp, t))
+	AssertNoError(err, t)
+	return f
+}
+
+func (f *authorizeFixture) authorize(ctx ConnContext) *AuthorizationDecision {
+	return Authorize(f.operator, f.account, f.user, ctx)
+}
+
+func TestAuthorize_Allowed(t *testing.T) {
+	f := newAuthorizeFixture(t)
+	d := f.authorize(ConnContext{NonceSigned: true})
+	AssertTrue(d.Allowed, t)
+	AssertEquals(0, len(d.Reasons), t)
+	AssertEquals(ConnectionTypeStandard, d.Context.ConnectionType, t)
+	AssertEquals("allowed", d.String(), t)
+
+	d = Authorize(f.operator, nil, f.user, ConnContext{})
+	AssertFalse(d.Allowed, t)
+	AssertTrue(d.Denied(DenyMissingClaims), t)
+}
+
+func TestAuthorize_OrderedReasons(t *testing.T) {
+	f := newAuthorizeFixture(t)
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	f.user.Expires = now.Add(-time.Hour).Unix()
+	f.user.NotBefore = now.Add(time.Hour).Unix()
+	f.account.RevokeAt(f.user.Subject, time.Unix(f.user.IssuedAt, 0).Add(time.Second))
+	f.user.BearerToken = true
+	f.account.Limits.DisallowBearer = true
+	f.user.Locale = "UTC"
+	f.user.Times = []TimeRange{{Start: "22:00:00", End: "06:00:00"}}
+	f.user.Src.Add("10.0.0.0/8")
+	f.user.AllowedConnectionTypes.Add(ConnectionTypeWebsocket)
+	f.user.Issuer = publicKey(createAccountNKey(t), t)
+
+	d := f.authorize(ConnContext{Time: now, SourceIP: net.ParseIP("192.168.0.1"), ConnectionType: ConnectionTypeMqtt})
+	AssertFalse(d.Allowed, t)
+	expected := []DenyCode{DenyExpired, DenyNotYetValid, DenyRevoked, DenyBearerDisallowed,
+		DenyTimeWindow, DenySourceIP, DenyConnectionType, DenyIssuer}
+	AssertEquals(len(expected), len(d.Reasons), t)
+	for i, c := range expected {
+		AssertEquals(c, d.Reasons[i].Code, t)
+	}
+}
+
+func TestAuthorize_Nonce(t *testing.T) {
+	f := newAuthorizeFixture(t)
+	d := f.authorize(ConnContext{})
+	AssertTrue(d.Denied(DenyNonceRequired), t)
+
+	// bearer tokens don't sign the nonce
+	f.user.BearerToken = true
+	d = f.authorize(ConnContext{})
+	AssertTrue(d.Allowed, t)
+}
+
+func TestAuthorize_AccountAndOperator(t *testing.T) {
+	f := newAuthorizeFixture(t)
+	f.account.Expires = time.Now().Add(-time.Minute).Unix()
+	d := f.authorize(ConnContext{NonceSigned: true})
+	AssertTrue(d.Denied(DenyExpired), t)
+
+	f = newAuthorizeFixture(t)
+	f.operator = NewOperatorClaims(publicKey(createOperatorNKey(t), t))
+	d = f.authorize(ConnContext{NonceSigned: true})
+	AssertTrue(d.Denied(DenyIssuer), t)
+	// without the operator only the account and user are evaluated
+	d = Authorize(nil, f.account, f.user, ConnContext{NonceSigned: true})
+	AssertTrue(d.Allowed, t)
+}
+
+func TestAuthorize_ScopedSigningKey(t *testing.T) {
+	akp := createAccountNKey(t)
+	skp := createAccountNKey(t)
+	account := NewAccountClaims(publicKey(akp, t))
+	scope := NewUserScope()
+	scope.Key = publicKey(skp, t)
+	scope.Template.AllowedConnectionTypes.Add(ConnectionTypeWebsocket)
+	account.SigningKeys.AddScopedSigner(scope)
+
+	uc := NewUserClaims(publicKey(createUserNKey(t), t))
+	uc.SetScoped(true)
+	uc.IssuerAccount = account.Subject
+	user, err := DecodeUserClaims(encode(uc, skp, t))
+	AssertNoError(err, t)
+
+	// the limits of the scope apply
+	d := Authorize(nil, account, user, ConnContext{NonceSigned: true})
+	AssertTrue(d.Denied(DenyConnectionType), t)
+	AssertTrue(d.Limits.AllowsConnectionType(ConnectionTypeWebsocket), t)
+	d = Authorize(nil, account, user, ConnContext{NonceSigned: true, ConnectionType: ConnectionTypeWebsocket})
+	AssertTrue(d.Allowed, t)
+
+	// scoped users can't carry their own permissions
+	user.Pub.Allow.Add("foo")
+	d = Authorize(nil, account, user, ConnContext{NonceSigned: true, ConnectionType: ConnectionTypeWebsocket})
+	AssertTrue(d.Denied(DenyIssuer), t)
+}
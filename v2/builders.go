@@ -0,0 +1,464 @@
+/*
+ * Copyright 2026 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nkeys"
+)
+
+// build validates the claims as issued by signer and encodes them, blocking issues are returned as error
+func build(c Claims, signer nkeys.KeyPair, fn SignFn) (string, error) {
+	if signer == nil {
+		return "", errors.New("signer is required")
+	}
+	pk, err := signer.PublicKey()
+	if err != nil {
+		return "", err
+	}
+	c.Claims().Issuer = pk
+	vr := CreateValidationResults()
+	c.Validate(vr)
+	if errs := vr.Errors(); len(errs) > 0 {
+		return "", errs[0]
+	}
+	return c.EncodeWithSigner(signer, fn)
+}
+
+func expiresIn(d time.Duration) int64 {
+	if d <= 0 {
+		return 0
+	}
+	return time.Now().Add(d).UTC().Unix()
+}
+
+// OperatorBuilder builds operator JWTs
+type OperatorBuilder struct {
+	claims *OperatorClaims
+	signFn SignFn
+}
+
+// NewOperatorBuilder creates a builder for the operator with the public key subject
+func NewOperatorBuilder(subject string) *OperatorBuilder {
+	c := NewOperatorClaims(subject)
+	if c == nil {
+		c = &OperatorClaims{}
+	}
+	return &OperatorBuilder{claims: c}
+}
+
+// Name sets the name of the operator
+func (b *OperatorBuilder) Name(name string) *OperatorBuilder {
+	b.claims.Name = name
+	return b
+}
+
+// Tags adds tags to the operator
+func (b *OperatorBuilder) Tags(tags ...string) *OperatorBuilder {
+	b.claims.Tags.Add(tags...)
+	return b
+}
+
+// Expiry sets the duration the operator JWT is valid for, 0 means it doesn't expire
+func (b *OperatorBuilder) Expiry(d time.Duration) *OperatorBuilder {
+	b.claims.Expires = expiresIn(d)
+	return b
+}
+
+// SigningKeys adds signing keys to the operator
+func (b *OperatorBuilder) SigningKeys(keys ...string) *OperatorBuilder {
+	b.claims.SigningKeys.Add(keys...)
+	return b
+}
+
+// StrictSigningKeyUsage requires accounts to be issued by signing keys
+func (b *OperatorBuilder) StrictSigningKeyUsage(strict bool) *OperatorBuilder {
+	b.claims.StrictSigningKeyUsage = strict
+	return b
+}
+
+// SystemAccount sets the public key of the system account
+func (b *OperatorBuilder) SystemAccount(account string) *OperatorBuilder {
+	b.claims.SystemAccount = account
+	return b
+}
+
+// AccountServerURL sets the url of the account server
+func (b *OperatorBuilder) AccountServerURL(url string) *OperatorBuilder {
+	b.claims.AccountServerURL = url
+	return b
+}
+
+// OperatorServiceURLs adds the urls of the nats servers of the operator
+func (b *OperatorBuilder) OperatorServiceURLs(urls ...string) *OperatorBuilder {
+	b.claims.OperatorServiceURLs.Add(urls...)
+	return b
+}
+
+// SignFn sets the function used to sign in an external sign environment
+func (b *OperatorBuilder) SignFn(fn SignFn) *OperatorBuilder {
+	b.signFn = fn
+	return b
+}
+
+// Claims returns the claims being built
+func (b *OperatorBuilder) Claims() *OperatorClaims {
+	return b.claims
+}
+
+// Build validates and encodes the operator, signed by the operator or one of its signing keys
+func (b *OperatorBuilder) Build(signer nkeys.KeyPair) (string, error) {
+	if signer == nil {
+		return "", errors.New("signer is required")
+	}
+	pk, err := signer.PublicKey()
+	if err != nil {
+		return "", err
+	}
+	if pk != b.claims.Subject && !b.claims.SigningKeys.Contains(pk) {
+		return "", fmt.Errorf("%q is not the operator %q or one of its signing keys", pk, b.claims.Subject)
+	}
+	return build(b.claims, signer, b.signFn)
+}
+
+// AccountBuilder builds account JWTs
+type AccountBuilder struct {
+	claims *AccountClaims
+	signFn SignFn
+}
+
+// NewAccountBuilder creates a builder for the account with the public key subject. Limits
+// start unlimited with JetStream disabled, as with NewAccountClaims.
+func NewAccountBuilder(subject string) *AccountBuilder {
+	c := NewAccountClaims(subject)
+	if c == nil {
+		// the missing subject is reported by Build
+		c = &AccountClaims{Account: Account{SigningKeys: SigningKeys{}, Mappings: Mapping{}}}
+	}
+	return &AccountBuilder{claims: c}
+}
+
+// Name sets the name of the account
+func (b *AccountBuilder) Name(name string) *AccountBuilder {
+	b.claims.Name = name
+	return b
+}
+
+// Description sets the description of the account
+func (b *AccountBuilder) Description(description string) *AccountBuilder {
+	b.claims.Description = description
+	return b
+}
+
+// Tags adds tags to the account
+func (b *AccountBuilder) Tags(tags ...string) *AccountBuilder {
+	b.claims.Tags.Add(tags...)
+	return b
+}
+
+// Expiry sets the duration the account JWT is valid for, 0 means it doesn't expire
+func (b *AccountBuilder) Expiry(d time.Duration) *AccountBuilder {
+	b.claims.Expires = expiresIn(d)
+	return b
+}
+
+// Exports adds exports to the account
+func (b *AccountBuilder) Exports(exports ...*Export) *AccountBuilder {
+	b.claims.Exports.Add(exports...)
+	return b
+}
+
+// Imports adds imports to the account
+func (b *AccountBuilder) Imports(imports ...*Import) *AccountBuilder {
+	b.claims.Imports.Add(imports...)
+	return b
+}
+
+// SigningKeys adds unscoped signing keys to the account
+func (b *AccountBuilder) SigningKeys(keys ...string) *AccountBuilder {
+	b.claims.SigningKeys.Add(keys...)
+	return b
+}
+
+// ScopedSigningKey adds a signing key that issues users with the permissions of the scope
+func (b *AccountBuilder) ScopedSigningKey(scope Scope) *AccountBuilder {
+	b.claims.SigningKeys.AddScopedSigner(scope)
+	return b
+}
+
+// Limits sets the limits of the account
+func (b *AccountBuilder) Limits(limits OperatorLimits) *AccountBuilder {
+	b.claims.Limits = limits
+	return b
+}
+
+// DefaultPermissions sets the permissions of users that don't specify any
+func (b *AccountBuilder) DefaultPermissions(p Permissions) *AccountBuilder {
+	b.claims.DefaultPermissions = p
+	return b
+}
+
+// Mapping adds a mapping of the subject to weighted destinations
+func (b *AccountBuilder) Mapping(subject Subject, to ...WeightedMapping) *AccountBuilder {
+	b.claims.AddMapping(subject, to...)
+	return b
+}
+
+// SignFn sets the function used to sign in an external sign environment
+func (b *AccountBuilder) SignFn(fn SignFn) *AccountBuilder {
+	b.signFn = fn
+	return b
+}
+
+// Claims returns the claims being built
+func (b *AccountBuilder) Claims() *AccountClaims {
+	return b.claims
+}
+
+// Build validates and encodes the account, signed by the operator or one of its signing keys
+func (b *AccountBuilder) Build(signer nkeys.KeyPair) (string, error) {
+	return build(b.claims, signer, b.signFn)
+}
+
+// UserBuilder builds user JWTs issued by an account
+type UserBuilder struct {
+	claims  *UserClaims
+	account *AccountClaims
+	limited bool
+	signFn  SignFn
+}
+
+// NewUserBuilder creates a builder for the user with the public key subject in the account
+func NewUserBuilder(subject string, account *AccountClaims) *UserBuilder {
+	c := NewUserClaims(subject)
+	if c == nil {
+		c = &UserClaims{}
+	}
+	return &UserBuilder{claims: c, account: account}
+}
+
+// Name sets the name of the user
+func (b *UserBuilder) Name(name string) *UserBuilder {
+	b.claims.Name = name
+	return b
+}
+
+// Tags adds tags to the user
+func (b *UserBuilder) Tags(tags ...string) *UserBuilder {
+	b.claims.Tags.Add(tags...)
+	return b
+}
+
+// Expiry sets the duration the user JWT is valid for, 0 means it doesn't expire
+func (b *UserBuilder) Expiry(d time.Duration) *UserBuilder {
+	b.claims.Expires = expiresIn(d)
+	return b
+}
+
+// Permissions sets the permissions of the user
+func (b *UserBuilder) Permissions(p Permissions) *UserBuilder {
+	b.claims.Permissions = p
+	b.limited = true
+	return b
+}
+
+// Limits sets the limits of the user
+func (b *UserBuilder) Limits(l Limits) *UserBuilder {
+	b.claims.Limits = l
+	b.limited = true
+	return b
+}
+
+// BearerToken allows the user to connect without signing the nonce
+func (b *UserBuilder) BearerToken(bearer bool) *UserBuilder {
+	b.claims.BearerToken = bearer
+	b.limited = true
+	return b
+}
+
+// AllowedConnectionTypes restricts the connection types of the user
+func (b *UserBuilder) AllowedConnectionTypes(types ...string) *UserBuilder {
+	b.claims.AllowedConnectionTypes.Add(types...)
+	b.limited = true
+	return b
+}
+
+// SignFn sets the function used to sign in an external sign environment
+func (b *UserBuilder) SignFn(fn SignFn) *UserBuilder {
+	b.signFn = fn
+	return b
+}
+
+// Claims returns the claims being built
+func (b *UserBuilder) Claims() *UserClaims {
+	return b.claims
+}
+
+// Build validates and encodes the user, signed by the account or one of its signing keys. The issuer
+// account is set if signer is a signing key. Users issued by a scoped signing key take the permissions
+// and limits of the scope, setting any on the builder is an error.
+func (b *UserBuilder) Build(signer nkeys.KeyPair) (string, error) {
+	if b.account == nil {
+		return "", errors.New("account is required")
+	}
+	if signer == nil {
+		return "", errors.New("signer is required")
+	}
+	pk, err := signer.PublicKey()
+	if err != nil {
+		return "", err
+	}
+	b.claims.IssuerAccount = ""
+	if pk != b.account.Subject {
+		scope, ok := b.account.SigningKeys.GetScope(pk)
+		if !ok {
+			return "", fmt.Errorf("%q is not the account %q or one of its signing keys", pk, b.account.Subject)
+		}
+		b.claims.IssuerAccount = b.account.Subject
+		if scope != nil {
+			if b.limited {
+				return "", fmt.Errorf("scoped signing key %q can't issue users with permissions or limits", pk)
+			}
+			b.claims.SetScoped(true)
+			b.claims.Issuer = pk
+			if err := scope.ValidateScopedSigner(b.claims); err != nil {
+				return "", err
+			}
+		}
+	}
+	return build(b.claims, signer, b.signFn)
+}
+
+// ActivationBuilder builds activation JWTs for an export
+type ActivationBuilder struct {
+	exporter *AccountClaims
+	export   *Export
+	importer string
+	opts     ActivationOptions
+	claims   *ActivationClaims
+}
+
+// NewActivationBuilder creates a builder for the activation of the export of the exporting account
+// by the importing account
+func NewActivationBuilder(exporter *AccountClaims, export *Export, importer string) *ActivationBuilder {
+	return &ActivationBuilder{exporter: exporter, export: export, importer: importer}
+}
+
+// Name sets the name of the activation
+func (b *ActivationBuilder) Name(name string) *ActivationBuilder {
+	b.opts.Name = name
+	return b
+}
+
+// Tags adds tags to the activation
+func (b *ActivationBuilder) Tags(tags ...string) *ActivationBuilder {
+	b.opts.Tags.Add(tags...)
+	return b
+}
+
+// Expiry sets the duration the activation is valid for, 0 means it doesn't expire
+func (b *ActivationBuilder) Expiry(d time.Duration) *ActivationBuilder {
+	b.opts.Expiry = d
+	return b
+}
+
+// Subject narrows the import subject to a subset of the exported subject
+func (b *ActivationBuilder) Subject(subject Subject) *ActivationBuilder {
+	b.opts.Subject = subject
+	return b
+}
+
+// SignFn sets the function used to sign in an external sign environment
+func (b *ActivationBuilder) SignFn(fn SignFn) *ActivationBuilder {
+	b.opts.SignFn = fn
+	return b
+}
+
+// Claims returns the claims of the last successful Build, nil before
+func (b *ActivationBuilder) Claims() *ActivationClaims {
+	return b.claims
+}
+
+// Build validates and encodes the activation, signed by the exporting account or one of its unscoped
+// signing keys. The issuer account is set if signer is a signing key. The import subject honours the
+// account token position of the export, importers revoked by the export are refused.
+func (b *ActivationBuilder) Build(signer nkeys.KeyPair) (string, error) {
+	e := b.export
+	if b.exporter == nil {
+		return "", errors.New("exporting account is required")
+	}
+	if e == nil {
+		return "", errors.New("export is required")
+	}
+	found := false
+	for _, x := range b.exporter.Exports {
+		if x == e {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return "", fmt.Errorf("export %q is not an export of account %q", e.Subject, b.exporter.Subject)
+	}
+	if !nkeys.IsValidPublicAccountKey(b.importer) {
+		return "", fmt.Errorf("%q is not an account public key", b.importer)
+	}
+	if signer == nil {
+		return "", errors.New("signer is required")
+	}
+	if _, ok := e.Revocations[b.importer]; ok || e.Revocations.IsRevoked(b.importer, time.Now()) {
+		return "", fmt.Errorf("account %q is revoked by export %q", b.importer, e.Subject)
+	}
+	subject, err := e.SubjectFor(b.importer)
+	if err != nil {
+		return "", err
+	}
+	if b.opts.Subject != "" {
+		if !b.opts.Subject.IsContainedIn(subject) {
+			return "", fmt.Errorf("subject %q is not covered by %q", b.opts.Subject, subject)
+		}
+		subject = b.opts.Subject
+	}
+	pk, err := signer.PublicKey()
+	if err != nil {
+		return "", err
+	}
+	act := NewActivationClaims(b.importer)
+	if pk != b.exporter.Subject {
+		scope, ok := b.exporter.SigningKeys.GetScope(pk)
+		if !ok {
+			return "", fmt.Errorf("%q is not the account %q or one of its signing keys", pk, b.exporter.Subject)
+		}
+		if scope != nil {
+			return "", fmt.Errorf("scoped signing key %q can't issue activations", pk)
+		}
+		act.IssuerAccount = b.exporter.Subject
+	}
+	act.Name = b.opts.Name
+	act.ImportSubject = subject
+	act.ImportType = e.Type
+	act.Tags.Add(b.opts.Tags...)
+	act.Expires = expiresIn(b.opts.Expiry)
+	token, err := build(act, signer, b.opts.SignFn)
+	if err != nil {
+		return "", err
+	}
+	b.claims = act
+	return token, nil
+}
@@ -0,0 +1,142 @@
+/*
+ * Copyright 2026 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwt
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOperatorBuilder(t *testing.T) {
+	okp := createOperatorNKey(t)
+	skp := createOperatorNKey(t)
+	sys := publicKey(createAccountNKey(t), t)
+	token, err := NewOperatorBuilder(publicKey(okp, t)).
+		Name("O").
+		SigningKeys(publicKey(skp, t)).
+		SystemAccount(sys).
+		Expiry(time.Hour).
+		Build(skp)
+	AssertNoError(err, t)
+	oc, err := DecodeOperatorClaims(token)
+	AssertNoError(err, t)
+	AssertEquals("O", oc.Name, t)
+	AssertEquals(sys, oc.SystemAccount, t)
+	AssertEquals(publicKey(skp, t), oc.Issuer, t)
+	AssertTrue(oc.Expires > time.Now().Unix(), t)
+
+	_, err = NewOperatorBuilder(publicKey(okp, t)).Build(createOperatorNKey(t))
+	AssertTrue(err != nil, t)
+	_, err = NewOperatorBuilder(publicKey(okp, t)).SystemAccount("bad").Build(okp)
+	AssertTrue(err != nil && strings.Contains(err.Error(), "is not an account public key"), t)
+}
+
+func TestAccountBuilder(t *testing.T) {
+	okp := createOperatorNKey(t)
+	akp := createAccountNKey(t)
+	token, err := NewAccountBuilder(publicKey(akp, t)).
+		Name("A").
+		Tags("one").
+		Exports(&Export{Subject: "svc.b", Type: Service}, &Export{Subject: "svc.a", Type: Service}).
+		Mapping("q", WeightedMapping{Subject: "q.v1"}).
+		Build(okp)
+	AssertNoError(err, t)
+	ac, err := DecodeAccountClaims(token)
+	AssertNoError(err, t)
+	AssertEquals("A", ac.Name, t)
+	AssertEquals(Subject("svc.a"), ac.Exports[0].Subject, t)
+	AssertEquals(1, len(ac.Mappings), t)
+	AssertEquals(int64(NoLimit), ac.Limits.Conn, t)
+	AssertFalse(ac.Limits.IsJSEnabled(), t)
+
+	// blocking validation issues refuse the build
+	_, err = NewAccountBuilder(publicKey(akp, t)).
+		Exports(&Export{Subject: "svc.>", Type: Service}, &Export{Subject: "svc.a", Type: Service}).
+		Build(okp)
+	AssertTrue(err != nil, t)
+	_, err = NewAccountBuilder("").Mapping("q", WeightedMapping{Subject: "q.v1"}).Build(okp)
+	AssertTrue(err != nil, t)
+}
+
+func TestUserBuilder(t *testing.T) {
+	akp := createAccountNKey(t)
+	skp := createAccountNKey(t)
+	scoped := createAccountNKey(t)
+	account := NewAccountClaims(publicKey(akp, t))
+	account.SigningKeys.Add(publicKey(skp, t))
+	scope := NewUserScope()
+	scope.Key = publicKey(scoped, t)
+	scope.Template.Pub.Allow.Add("scoped.>")
+	account.SigningKeys.AddScopedSigner(scope)
+
+	upk := publicKey(createUserNKey(t), t)
+	p := Permissions{}
+	p.Pub.Allow.Add("foo")
+	token, err := NewUserBuilder(upk, account).Name("U").Permissions(p).Build(akp)
+	AssertNoError(err, t)
+	uc, err := DecodeUserClaims(token)
+	AssertNoError(err, t)
+	AssertEquals("", uc.IssuerAccount, t)
+	AssertTrue(uc.Pub.Allow.Contains("foo"), t)
+
+	token, err = NewUserBuilder(upk, account).Build(skp)
+	AssertNoError(err, t)
+	uc, err = DecodeUserClaims(token)
+	AssertNoError(err, t)
+	AssertEquals(account.Subject, uc.IssuerAccount, t)
+	AssertEquals(int64(NoLimit), uc.Subs, t)
+
+	b := NewUserBuilder(upk, account)
+	token, err = b.Build(scoped)
+	AssertNoError(err, t)
+	uc, err = DecodeUserClaims(token)
+	AssertNoError(err, t)
+	AssertEquals(account.Subject, uc.IssuerAccount, t)
+	AssertTrue(uc.HasEmptyPermissions(), t)
+	AssertTrue(account.DidSign(uc), t)
+
+	_, err = NewUserBuilder(upk, account).Permissions(p).Build(scoped)
+	AssertTrue(err != nil && strings.Contains(err.Error(), "can't issue users with permissions"), t)
+	_, err = NewUserBuilder(upk, account).Build(createAccountNKey(t))
+	AssertTrue(err != nil, t)
+	_, err = NewUserBuilder(upk, nil).Build(akp)
+	AssertTrue(err != nil, t)
+}
+
+func TestActivationBuilder(t *testing.T) {
+	akp := createAccountNKey(t)
+	skp := createAccountNKey(t)
+	exporter := NewAccountClaims(publicKey(akp, t))
+	exporter.SigningKeys.Add(publicKey(skp, t))
+	e := &Export{Subject: "svc.>", Type: Service, TokenReq: true}
+	exporter.Exports.Add(e)
+	importer := publicKey(createAccountNKey(t), t)
+
+	b := NewActivationBuilder(exporter, e, importer).Name("act").Subject("svc.q").Expiry(time.Hour)
+	token, err := b.Build(skp)
+	AssertNoError(err, t)
+	act, err := DecodeActivationClaims(token)
+	AssertNoError(err, t)
+	AssertEquals(exporter.Subject, act.IssuerAccount, t)
+	AssertEquals(Subject("svc.q"), act.ImportSubject, t)
+	AssertEquals("act", b.Claims().Name, t)
+
+	_, err = NewActivationBuilder(exporter, e, importer).Subject("other").Build(akp)
+	AssertTrue(err != nil, t)
+	_, err = NewActivationBuilder(exporter, &Export{Subject: "x", Type: Service}, importer).Build(akp)
+	AssertTrue(err != nil, t)
+}
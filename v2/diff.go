@@ -0,0 +1,321 @@
+/*
+ * Copyright 2026 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwt
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// DiffOp is the kind of a difference between two claims
+type DiffOp string
+
+const (
+	DiffAdded   DiffOp = "added"
+	DiffRemoved DiffOp = "removed"
+	DiffChanged DiffOp = "changed"
+)
+
+// DiffEntry is a single difference between two claims
+type DiffEntry struct {
+	// Path locates the value using the JSON field names, e.g. nats.exports[service:foo.>].response_type.
+	// Elements of exports, imports, signing keys and maps are addressed by their key in brackets, exports
+	// by type and subject, imports by type, account, subject and local subject (or to) when set.
+	Path string      `json:"path"`
+	Op   DiffOp      `json:"op"`
+	Old  interface{} `json:"old,omitempty"`
+	New  interface{} `json:"new,omitempty"`
+}
+
+func (d *DiffEntry) String() string {
+	switch d.Op {
+	case DiffAdded:
+		return fmt.Sprintf("+ %s: %s", d.Path, diffValueString(d.New))
+	case DiffRemoved:
+		return fmt.Sprintf("- %s: %s", d.Path, diffValueString(d.Old))
+	default:
+		return fmt.Sprintf("~ %s: %s -> %s", d.Path, diffValueString(d.Old), diffValueString(d.New))
+	}
+}
+
+func diffValueString(v interface{}) string {
+	d, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(d)
+}
+
+// diffIgnored fields change on every encode and carry no meaning
+var diffIgnored = map[string]bool{"jti": true, "iat": true}
+
+// keyedMap holds elements addressed by key rather than by field name
+type keyedMap map[string]interface{}
+
+// stringSet holds the values of a list of strings, for which the order is not relevant
+type stringSet map[string]bool
+
+// Diff returns the semantic differences from claim a to claim b, which need to be of the same type.
+// The issue time and id are ignored, as is the order of exports, imports, signing keys, mappings
+// and lists of strings such as tags and permissions.
+func Diff(a Claims, b Claims) ([]*DiffEntry, error) {
+	if a == nil || b == nil {
+		return nil, errors.New("claims are required")
+	}
+	if reflect.TypeOf(a) != reflect.TypeOf(b) {
+		return nil, fmt.Errorf("can't compare %T with %T", a, b)
+	}
+	av, err := diffNormalized(a)
+	if err != nil {
+		return nil, err
+	}
+	bv, err := diffNormalized(b)
+	if err != nil {
+		return nil, err
+	}
+	var r []*DiffEntry
+	diffValues("", av, bv, &r)
+	return r, nil
+}
+
+func diffNormalized(c Claims) (interface{}, error) {
+	d, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(d))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return normalize("", v), nil
+}
+
+// elementKey returns the key identifying an element of a keyed list
+func elementKey(field string, v interface{}) string {
+	switch e := v.(type) {
+	case string:
+		return e
+	case map[string]interface{}:
+		switch field {
+		case "signing_keys":
+			return fmt.Sprintf("%v", e["key"])
+		case "exports":
+			return fmt.Sprintf("%v:%v", e["type"], e["subject"])
+		case "imports":
+			k := fmt.Sprintf("%v:%v:%v", e["type"], e["account"], e["subject"])
+			if l, ok := e["local_subject"]; ok {
+				k = fmt.Sprintf("%s:%v", k, l)
+			} else if to, ok := e["to"]; ok {
+				k = fmt.Sprintf("%s:%v", k, to)
+			}
+			return k
+		case "mappings":
+			if c, ok := e["cluster"]; ok {
+				return fmt.Sprintf("%v@%v", e["subject"], c)
+			}
+		}
+		return fmt.Sprintf("%v", e["subject"])
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// keyed converts a list into a keyedMap, repeated keys are numbered in order
+func keyed(field string, list []interface{}) keyedMap {
+	m := keyedMap{}
+	for _, e := range list {
+		k := elementKey(field, e)
+		if _, ok := m[k]; ok {
+			n := 2
+			for ; ; n++ {
+				if _, ok := m[fmt.Sprintf("%s#%d", k, n)]; !ok {
+					break
+				}
+			}
+			k = fmt.Sprintf("%s#%d", k, n)
+		}
+		m[k] = normalize("", e)
+	}
+	return m
+}
+
+func normalize(field string, v interface{}) interface{} {
+	switch e := v.(type) {
+	case map[string]interface{}:
+		switch field {
+		case "revocations", "tiered_limits":
+			m := keyedMap{}
+			for k, x := range e {
+				m[k] = normalize("", x)
+			}
+			return m
+		case "mappings":
+			m := keyedMap{}
+			for k, x := range e {
+				if l, ok := x.([]interface{}); ok {
+					m[k] = keyed("mappings", l)
+				} else {
+					m[k] = normalize("", x)
+				}
+			}
+			return m
+		}
+		m := make(map[string]interface{}, len(e))
+		for k, x := range e {
+			if field == "" && diffIgnored[k] {
+				continue
+			}
+			m[k] = normalize(k, x)
+		}
+		return m
+	case []interface{}:
+		switch field {
+		case "exports", "imports", "signing_keys":
+			return keyed(field, e)
+		}
+		set := stringSet{}
+		for _, x := range e {
+			s, ok := x.(string)
+			if !ok {
+				l := make([]interface{}, len(e))
+				for i, x := range e {
+					l[i] = normalize("", x)
+				}
+				return l
+			}
+			set[s] = true
+		}
+		return set
+	}
+	return v
+}
+
+// plain converts normalized values back to their JSON form for reporting
+func plain(v interface{}) interface{} {
+	switch e := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(e))
+		for k, x := range e {
+			m[k] = plain(x)
+		}
+		return m
+	case keyedMap:
+		m := make(map[string]interface{}, len(e))
+		for k, x := range e {
+			m[k] = plain(x)
+		}
+		return m
+	case stringSet:
+		return sortedKeys(e)
+	case []interface{}:
+		l := make([]interface{}, len(e))
+		for i, x := range e {
+			l[i] = plain(x)
+		}
+		return l
+	}
+	return v
+}
+
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	for _, k := range reflect.ValueOf(m).MapKeys() {
+		keys = append(keys, k.String())
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func fieldPath(path string, k string) string {
+	if path == "" {
+		return k
+	}
+	return path + "." + k
+}
+
+func diffValues(path string, a interface{}, b interface{}, r *[]*DiffEntry) {
+	switch av := a.(type) {
+	case map[string]interface{}:
+		if bv, ok := b.(map[string]interface{}); ok {
+			diffMaps(av, bv, func(k string) string { return fieldPath(path, k) }, r)
+			return
+		}
+	case keyedMap:
+		if bv, ok := b.(keyedMap); ok {
+			diffMaps(av, bv, func(k string) string { return fmt.Sprintf("%s[%s]", path, k) }, r)
+			return
+		}
+	case stringSet:
+		if bv, ok := b.(stringSet); ok {
+			for _, k := range sortedKeys(av) {
+				if !bv[k] {
+					*r = append(*r, &DiffEntry{Path: path, Op: DiffRemoved, Old: k})
+				}
+			}
+			for _, k := range sortedKeys(bv) {
+				if !av[k] {
+					*r = append(*r, &DiffEntry{Path: path, Op: DiffAdded, New: k})
+				}
+			}
+			return
+		}
+	case []interface{}:
+		if bv, ok := b.([]interface{}); ok {
+			for i := 0; i < len(av) || i < len(bv); i++ {
+				p := fmt.Sprintf("%s[%d]", path, i)
+				switch {
+				case i >= len(bv):
+					*r = append(*r, &DiffEntry{Path: p, Op: DiffRemoved, Old: plain(av[i])})
+				case i >= len(av):
+					*r = append(*r, &DiffEntry{Path: p, Op: DiffAdded, New: plain(bv[i])})
+				default:
+					diffValues(p, av[i], bv[i], r)
+				}
+			}
+			return
+		}
+	}
+	if !reflect.DeepEqual(a, b) {
+		*r = append(*r, &DiffEntry{Path: path, Op: DiffChanged, Old: plain(a), New: plain(b)})
+	}
+}
+
+func diffMaps(a map[string]interface{}, b map[string]interface{}, path func(k string) string, r *[]*DiffEntry) {
+	keys := make(map[string]bool, len(a)+len(b))
+	for k := range a {
+		keys[k] = true
+	}
+	for k := range b {
+		keys[k] = true
+	}
+	for _, k := range sortedKeys(keys) {
+		av, inA := a[k]
+		bv, inB := b[k]
+		switch {
+		case !inB:
+			*r = append(*r, &DiffEntry{Path: path(k), Op: DiffRemoved, Old: plain(av)})
+		case !inA:
+			*r = append(*r, &DiffEntry{Path: path(k), Op: DiffAdded, New: plain(bv)})
+		default:
+			diffValues(path(k), av, bv, r)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+/*
+ * Copyright 2026 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwt
+
+import (
+	"testing"
+	"time"
+)
+
+func findDiff(d []*DiffEntry, path string, op DiffOp) *DiffEntry {
+	for _, e := range d {
+		if e.Path == path && e.Op == op {
+			return e
+		}
+	}
+	return nil
+}
+
+func TestDiff_Account(t *testing.T) {
+	akp := createAccountNKey(t)
+	okp := createOperatorNKey(t)
+	a := NewAccountClaims(publicKey(akp, t))
+	a.Exports.Add(&Export{Subject: "svc.a", Type: Service}, &Export{Subject: "svc.b", Type: Service})
+	a.Imports.Add(&Import{Subject: "events", Account: publicKey(createAccountNKey(t), t), Type: Stream})
+	a.Tags.Add("one", "two")
+	sk := publicKey(createAccountNKey(t), t)
+	a.SigningKeys.Add(sk)
+	a.Mappings = Mapping{"q": {{Subject: "q.v1", Weight: 50}, {Subject: "q.v2", Weight: 50}}}
+
+	a1, err := DecodeAccountClaims(encode(a, okp, t))
+	AssertNoError(err, t)
+	time.Sleep(time.Second)
+	// the same content in a different order and with a new id and issue time is unchanged
+	b := *a1
+	b.Exports = Exports{a1.Exports[1], a1.Exports[0]}
+	b.Tags = TagList{"two", "one"}
+	b.Mappings = Mapping{"q": {a1.Mappings["q"][1], a1.Mappings["q"][0]}}
+	b2, err := DecodeAccountClaims(encode(&b, okp, t))
+	AssertNoError(err, t)
+	d, err := Diff(a1, b2)
+	AssertNoError(err, t)
+	AssertEquals(0, len(d), t)
+
+	// semantic changes
+	for _, e := range b2.Exports {
+		if e.Subject == "svc.b" {
+			e.ResponseType = ResponseTypeStream
+		}
+	}
+	b2.Exports = append(b2.Exports, &Export{Subject: "svc.c", Type: Service})
+	b2.Imports = nil
+	b2.Tags.Add("three")
+	b2.Tags.Remove("one")
+	scope := NewUserScope()
+	scope.Key = sk
+	b2.SigningKeys.AddScopedSigner(scope)
+	b2.Revoke(publicKey(createUserNKey(t), t))
+	b2.Mappings["q"] = []WeightedMapping{{Subject: "q.v1", Weight: 60}, {Subject: "q.v2", Weight: 40}}
+	b2.DefaultPermissions.Pub.Allow.Add("foo")
+	b2.Description = "changed"
+
+	d, err = Diff(a1, b2)
+	AssertNoError(err, t)
+	AssertTrue(findDiff(d, "nats.exports[service:svc.b].response_type", DiffAdded) != nil, t)
+	AssertTrue(findDiff(d, "nats.exports[service:svc.c]", DiffAdded) != nil, t)
+	AssertTrue(findDiff(d, "nats.imports", DiffRemoved) != nil, t)
+	AssertTrue(findDiff(d, "nats.tags", DiffAdded) != nil, t)
+	AssertEquals("one", findDiff(d, "nats.tags", DiffRemoved).Old, t)
+	AssertTrue(findDiff(d, "nats.signing_keys["+sk+"]", DiffChanged) != nil, t)
+	AssertTrue(findDiff(d, "nats.revocations", DiffAdded) != nil, t)
+	AssertTrue(findDiff(d, "nats.mappings[q][q.v1].weight", DiffChanged) != nil, t)
+	AssertTrue(findDiff(d, "nats.default_permissions.pub.allow", DiffAdded) != nil, t)
+	AssertTrue(findDiff(d, "nats.description", DiffAdded) != nil, t)
+	for _, e := range d {
+		AssertTrue(e.String() != "", t)
+	}
+}
+
+func TestDiff_SameSubjectOrder(t *testing.T) {
+	akp := createAccountNKey(t)
+	bpk := publicKey(createAccountNKey(t), t)
+	cpk := publicKey(createAccountNKey(t), t)
+	a := NewAccountClaims(publicKey(akp, t))
+	a.Exports.Add(&Export{Subject: "foo", Type: Service}, &Export{Subject: "foo", Type: Stream})
+	a.Imports.Add(&Import{Subject: "bar", Account: bpk, Type: Stream},
+		&Import{Subject: "bar", Account: cpk, Type: Stream, LocalSubject: "c.bar"})
+	b := NewAccountClaims(a.Subject)
+	b.Exports.Add(a.Exports[1], a.Exports[0])
+	b.Imports.Add(a.Imports[1], a.Imports[0])
+
+	d, err := Diff(a, b)
+	AssertNoError(err, t)
+	AssertEquals(0, len(d), t)
+
+	c := *a.Imports[1]
+	c.Share = true
+	b.Imports[0] = &c
+	d, err = Diff(a, b)
+	AssertNoError(err, t)
+	AssertEquals(1, len(d), t)
+	AssertEquals("nats.imports[stream:"+cpk+":bar:c.bar].share", d[0].Path, t)
+}
+
+func TestDiff_SameSubjectLocalSubjectOrder(t *testing.T) {
+	bpk := publicKey(createAccountNKey(t), t)
+	a := NewAccountClaims(publicKey(createAccountNKey(t), t))
+	a.Imports.Add(&Import{Subject: "bar", Account: bpk, Type: Stream, LocalSubject: "x.bar"},
+		&Import{Subject: "bar", Account: bpk, Type: Stream, LocalSubject: "y.bar"})
+	b := NewAccountClaims(a.Subject)
+	b.Imports.Add(a.Imports[1], a.Imports[0])
+
+	d, err := Diff(a, b)
+	AssertNoError(err, t)
+	AssertEquals(0, len(d), t)
+
+	c := *a.Imports[0]
+	c.LocalSubject = "z.bar"
+	b.Imports[1] = &c
+	d, err = Diff(a, b)
+	AssertNoError(err, t)
+	AssertEquals(2, len(d), t)
+	AssertTrue(findDiff(d, "nats.imports[stream:"+bpk+":bar:x.bar]", DiffRemoved) != nil, t)
+	AssertTrue(findDiff(d, "nats.imports[stream:"+bpk+":bar:z.bar]", DiffAdded) != nil, t)
+}
+
+func TestDiff_User(t *testing.T) {
+	akp := createAccountNKey(t)
+	u := NewUserClaims(publicKey(createUserNKey(t), t))
+	u.Pub.Allow.Add("a", "b")
+	u1, err := DecodeUserClaims(encode(u, akp, t))
+	AssertNoError(err, t)
+	u2, err := DecodeUserClaims(encode(u, akp, t))
+	AssertNoError(err, t)
+	u2.Pub.Allow = StringList{"b", "c"}
+	u2.Expires = 100
+	u2.Limits.Payload = 1024
+
+	d, err := Diff(u1, u2)
+	AssertNoError(err, t)
+	AssertEquals(4, len(d), t)
+	AssertEquals("- nats.pub.allow: \"a\"", findDiff(d, "nats.pub.allow", DiffRemoved).String(), t)
+	AssertEquals("+ nats.pub.allow: \"c\"", findDiff(d, "nats.pub.allow", DiffAdded).String(), t)
+	AssertEquals("+ exp: 100", findDiff(d, "exp", DiffAdded).String(), t)
+	AssertEquals("~ nats.payload: -1 -> 1024", findDiff(d, "nats.payload", DiffChanged).String(), t)
+}
+
+func TestDiff_Errors(t *testing.T) {
+	a := NewAccountClaims(publicKey(createAccountNKey(t), t))
+	u := NewUserClaims(publicKey(createUserNKey(t), t))
+	_, err := Diff(a, u)
+	AssertTrue(err != nil, t)
+	_, err = Diff(a, nil)
+	AssertTrue(err != nil, t)
+}
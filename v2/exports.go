@@ -20,6 +20,8 @@ import (
 	"fmt"
 	"strings"
 	"time"
+
+	"github.com/nats-io/nkeys"
 )
 
 // ResponseType is used to store an export response type
@@ -201,6 +203,67 @@ func (e *Export) Validate(vr *ValidationResults) {
 	e.Info.Validate(vr)
 }
 
+// SubjectFor returns the subject the importing account needs to import. If the export uses an
+// account token position, the token at that position is replaced by the importer's public key.
+func (e *Export) SubjectFor(importerPubKey string) (Subject, error) {
+	if e.AccountTokenPosition == 0 {
+		return e.Subject, nil
+	}
+	if !nkeys.IsValidPublicAccountKey(importerPubKey) {
+		return "", fmt.Errorf("%q is not an account public key", importerPubKey)
+	}
+	tokens := strings.Split(string(e.Subject), ".")
+	if e.AccountTokenPosition > uint(len(tokens)) || tokens[e.AccountTokenPosition-1] != "*" {
+		return "", fmt.Errorf("account token position %d does not match a * in %q", e.AccountTokenPosition, e.Subject)
+	}
+	tokens[e.AccountTokenPosition-1] = importerPubKey
+	return Subject(strings.Join(tokens, ".")), nil
+}
+
+// ImporterFromSubject returns the public key of the importing account contained in the subject
+// at the account token position of the export. The subject needs to match the export.
+func (e *Export) ImporterFromSubject(subject Subject) (string, error) {
+	if e.AccountTokenPosition == 0 {
+		return "", fmt.Errorf("export %q has no account token position", e.Subject)
+	}
+	if !subject.IsContainedIn(e.Subject) {
+		return "", fmt.Errorf("subject %q does not match export %q", subject, e.Subject)
+	}
+	tokens := strings.Split(string(subject), ".")
+	if e.AccountTokenPosition > uint(len(tokens)) {
+		return "", fmt.Errorf("account token position %d exceeds length of subject %q", e.AccountTokenPosition, subject)
+	}
+	pk := tokens[e.AccountTokenPosition-1]
+	if !nkeys.IsValidPublicAccountKey(pk) {
+		return "", fmt.Errorf("token %q at account token position %d of %q is not an account public key",
+			pk, e.AccountTokenPosition, subject)
+	}
+	return pk, nil
+}
+
+// ActivationOptions customizes activation tokens issued by Export.IssueActivation
+type ActivationOptions struct {
+	Name string
+	// Expiry is the duration the activation is valid for, 0 means it doesn't expire
+	Expiry time.Duration
+	// Subject optionally narrows the import subject to a subset of the exported subject
+	Subject Subject
+	Tags    TagList
+	// SignFn is used to sign the activation in an external sign environment
+	SignFn SignFn
+}
+
+// IssueActivation creates an activation token for the importing account, signed by the exporting
+// account or one of its signing keys. The import subject honours the account token position of the
+// export. Importers currently revoked by the export are refused, use ClearRevocation to issue again.
+func (e *Export) IssueActivation(exporter *AccountClaims, importer string, signer nkeys.KeyPair, opts *ActivationOptions) (string, error) {
+	b := NewActivationBuilder(exporter, e, importer)
+	if opts != nil {
+		b.opts = *opts
+	}
+	return b.Build(signer)
+}
+
 // Revoke enters a revocation by publickey using time.Now().
 func (e *Export) Revoke(pubKey string) {
 	e.RevokeAt(pubKey, time.Now())
@@ -246,18 +309,21 @@ func (e *Exports) Add(i ...*Export) {
 }
 
 func isContainedIn(kind ExportType, subjects []Subject, vr *ValidationResults) {
+	sl := NewSublist()
+	for i, s := range subjects {
+		sl.Insert(s, i)
+	}
 	m := make(map[string]string)
 	for i, ns := range subjects {
-		for j, s := range subjects {
+		for _, v := range sl.Match(ns) {
+			j := v.(int)
 			if i == j {
 				continue
 			}
-			if ns.IsContainedIn(s) {
-				str := string(s)
-				_, ok := m[str]
-				if !ok {
-					m[str] = string(ns)
-				}
+			str := string(subjects[j])
+			_, ok := m[str]
+			if !ok {
+				m[str] = string(ns)
 			}
 		}
 	}
@@ -270,6 +336,21 @@ func isContainedIn(kind ExportType, subjects []Subject, vr *ValidationResults) {
 			vr.Add(&vi)
 		}
 	}
+
+	// subjects that overlap without one containing the other
+	for i, ns := range subjects {
+		for _, v := range sl.Overlaps(ns) {
+			j := v.(int)
+			if j <= i {
+				continue
+			}
+			s := subjects[j]
+			if ns.IsContainedIn(s) || s.IsContainedIn(ns) {
+				continue
+			}
+			vr.AddError("%s export subject %q overlaps %q on %q", kind, ns, s, ns.Intersection(s))
+		}
+	}
 }
 
 // Validate calls validate on all of the exports
@@ -294,7 +375,57 @@ func (e *Exports) Validate(vr *ValidationResults) {
 	isContainedIn(Stream, streamSubjects, vr)
 }
 
-// HasExportContainingSubject checks if the export list has an export with the provided subject
+// Sublist returns a Sublist holding the *Export values of the list keyed by their subject.
+// Use it instead of HasExportContainingSubject when looking up many subjects.
+func (e *Exports) Sublist() *Sublist {
+	sl := NewSublist()
+	for _, s := range *e {
+		if s != nil {
+			sl.Insert(s.Subject, s)
+		}
+	}
+	return sl
+}
+
+// exportIndex finds the exports containing a subject without scanning every export
+type exportIndex struct {
+	exports Exports
+	sl      *Sublist
+}
+
+func newExportIndex(exports Exports) *exportIndex {
+	x := &exportIndex{exports: exports, sl: NewSublist()}
+	for idx, e := range exports {
+		if e != nil {
+			x.sl.Insert(e.Subject, idx)
+		}
+	}
+	return x
+}
+
+// find returns the first export in list order that contains the subject and has the type,
+// any type matches if typ is Unknown
+func (x *exportIndex) find(subject Subject, typ ExportType) *Export {
+	first := -1
+	for _, v := range x.sl.Match(subject) {
+		idx := v.(int)
+		if (typ == Unknown || x.exports[idx].Type == typ) && (first == -1 || idx < first) {
+			first = idx
+		}
+	}
+	if first == -1 {
+		return nil
+	}
+	return x.exports[first]
+}
+
+// findImport returns the export the import resolves to
+func (x *exportIndex) findImport(i *Import) *Export {
+	return x.find(i.remoteSubject(), i.Type)
+}
+
+// HasExportContainingSubject checks if the export list has an export with the provided subject.
+// It scans the list, use Sublist when looking up many subjects.
 func (e *Exports) HasExportContainingSubject(subject Subject) bool {
 	for _, s := range *e {
 		if subject.IsContainedIn(s.Subject) {
@@ -149,6 +149,21 @@ func TestOverlappingExports(t *testing.T) {
 	}
 }
 
+func TestPartiallyOverlappingExports(t *testing.T) {
+	exports := &Exports{}
+	exports.Add(&Export{Subject: "a.*.c", Type: Stream}, &Export{Subject: "a.b.*", Type: Stream})
+
+	vr := CreateValidationResults()
+	exports.Validate(vr)
+
+	if len(vr.Issues) != 1 || !vr.IsBlocking(false) {
+		t.Fatalf("expected partially overlapping subjects to be a blocking issue")
+	}
+	if !strings.Contains(vr.Issues[0].Description, `on "a.b.c"`) {
+		t.Fatalf("expected the overlapping subject to be reported: %s", vr.Issues[0].Description)
+	}
+}
+
 func TestDifferentExportTypes_OverlapOK(t *testing.T) {
 	i := &Export{Subject: "bar.foo", Type: Service}
 	i2 := &Export{Subject: "bar.*", Type: Stream}
@@ -462,3 +477,90 @@ func TestExportAllowTrace(t *testing.T) {
 		t.Fatalf("validation should have been ok, got %+v", vr.Issues)
 	}
 }
+
+func TestExport_SubjectFor(t *testing.T) {
+	importer := publicKey(createAccountNKey(t), t)
+
+	e := &Export{Subject: "foo.bar", Type: Service}
+	s, err := e.SubjectFor(importer)
+	AssertNoError(err, t)
+	AssertEquals(Subject("foo.bar"), s, t)
+	_, err = e.ImporterFromSubject("foo.bar")
+	AssertTrue(err != nil, t)
+
+	e = &Export{Subject: "tenant.*.req.*", Type: Service, AccountTokenPosition: 2}
+	s, err = e.SubjectFor(importer)
+	AssertNoError(err, t)
+	AssertEquals(Subject("tenant."+importer+".req.*"), s, t)
+	pk, err := e.ImporterFromSubject(s)
+	AssertNoError(err, t)
+	AssertEquals(importer, pk, t)
+	pk, err = e.ImporterFromSubject(Subject("tenant." + importer + ".req.x"))
+	AssertNoError(err, t)
+	AssertEquals(importer, pk, t)
+
+	_, err = e.SubjectFor("not-an-account")
+	AssertTrue(err != nil, t)
+	_, err = e.ImporterFromSubject("tenant.foo.req.x")
+	AssertTrue(err != nil, t)
+	_, err = e.ImporterFromSubject("other." + Subject(importer) + ".req.x")
+	AssertTrue(err != nil, t)
+
+	e.AccountTokenPosition = 3
+	_, err = e.SubjectFor(importer)
+	AssertTrue(err != nil, t)
+}
+
+func TestExport_IssueActivation(t *testing.T) {
+	akp := createAccountNKey(t)
+	skp := createAccountNKey(t)
+	exporter := NewAccountClaims(publicKey(akp, t))
+	exporter.SigningKeys.Add(publicKey(skp, t))
+	importer := publicKey(createAccountNKey(t), t)
+	e := &Export{Subject: "tenant.*.>", Type: Service, TokenReq: true, AccountTokenPosition: 2}
+	exporter.Exports.Add(e)
+
+	token, err := e.IssueActivation(exporter, importer, akp, &ActivationOptions{Expiry: time.Hour, Name: "tenant"})
+	AssertNoError(err, t)
+	act, err := DecodeActivationClaims(token)
+	AssertNoError(err, t)
+	AssertEquals(importer, act.Subject, t)
+	AssertEquals(Subject("tenant."+importer+".>"), act.ImportSubject, t)
+	AssertEquals(Service, act.ImportType, t)
+	AssertEquals("", act.IssuerAccount, t)
+	AssertEquals("tenant", act.Name, t)
+	AssertTrue(act.Expires > time.Now().Unix(), t)
+	AssertTrue(exporter.DidSign(act), t)
+
+	// signing keys set the issuer account
+	token, err = e.IssueActivation(exporter, importer, skp, &ActivationOptions{Subject: Subject("tenant." + importer + ".q")})
+	AssertNoError(err, t)
+	act, err = DecodeActivationClaims(token)
+	AssertNoError(err, t)
+	AssertEquals(exporter.Subject, act.IssuerAccount, t)
+	AssertEquals(Subject("tenant."+importer+".q"), act.ImportSubject, t)
+	AssertTrue(exporter.DidSign(act), t)
+
+	// the token works for an import
+	i := &Import{Subject: act.ImportSubject, Account: exporter.Subject, Type: Service, Token: token}
+	vr := CreateValidationResults()
+	i.ValidateAgainst(importer, exporter, vr)
+	AssertTrue(vr.IsEmpty(), t)
+
+	// narrowing outside of the export is refused
+	_, err = e.IssueActivation(exporter, importer, akp, &ActivationOptions{Subject: "tenant.other.q"})
+	AssertTrue(err != nil, t)
+	// unknown signers are refused
+	_, err = e.IssueActivation(exporter, importer, createAccountNKey(t), nil)
+	AssertTrue(err != nil, t)
+	// exports of other accounts are refused
+	_, err = (&Export{Subject: "foo", Type: Stream}).IssueActivation(exporter, importer, akp, nil)
+	AssertTrue(err != nil, t)
+	// revoked importers are refused
+	e.Revoke(importer)
+	_, err = e.IssueActivation(exporter, importer, akp, nil)
+	AssertTrue(err != nil, t)
+	e.ClearRevocation(importer)
+	_, err = e.IssueActivation(exporter, importer, akp, nil)
+	AssertNoError(err, t)
+}
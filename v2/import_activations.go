@@ -0,0 +1,165 @@
+/*
+ * Copyright 2026 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwt
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// ImportActivation describes the activation token embedded in an import
+type ImportActivation struct {
+	Import *Import
+	// Activation is the decoded token, nil if it could not be decoded
+	Activation *ActivationClaims
+	// Expires is the expiration of the token, zero if the token doesn't expire
+	Expires time.Time
+	Expired bool
+	// Err is set if the token could not be decoded
+	Err error
+}
+
+// ScanImportActivations returns the activation tokens of the account's imports that are
+// expired or expire within the window. Tokens that can't be decoded are returned with Err set.
+func ScanImportActivations(account *AccountClaims, window time.Duration) []*ImportActivation {
+	return ScanImportActivationsWithResolver(account, window, nil)
+}
+
+// ScanImportActivationsWithResolver is like ScanImportActivations, using the resolver
+// to look up the activations referenced by the imports
+func ScanImportActivationsWithResolver(account *AccountClaims, window time.Duration, resolver ActivationResolver) []*ImportActivation {
+	var r []*ImportActivation
+	if account == nil {
+		return r
+	}
+	now := time.Now()
+	for _, i := range account.Imports {
+		if i == nil || i.Token == "" {
+			continue
+		}
+		act, err := i.ResolveActivation(resolver)
+		if err != nil {
+			r = append(r, &ImportActivation{Import: i, Err: err})
+			continue
+		}
+		if act.Expires == 0 {
+			continue
+		}
+		exp := time.Unix(act.Expires, 0)
+		if exp.After(now.Add(window)) {
+			continue
+		}
+		r = append(r, &ImportActivation{Import: i, Activation: act, Expires: exp, Expired: !exp.After(now)})
+	}
+	return r
+}
+
+// ActivationSigner signs the renewed activation on behalf of the exporting account and
+// returns the encoded token. It is typically implemented by calling act.Encode with the
+// key pair of the exporter or one of its signing keys.
+type ActivationSigner func(exporter string, act *ActivationClaims) (string, error)
+
+// ActivationRenewal reports the outcome of renewing the activation token of an import
+type ActivationRenewal struct {
+	Subject         Subject
+	Account         string
+	PreviousExpires int64
+	Expires         int64
+	// Err is set if the activation could not be renewed, the import keeps its token
+	Err error
+}
+
+func cloneAccountClaims(a *AccountClaims) (*AccountClaims, error) {
+	d, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+	c := &AccountClaims{}
+	if err := json.Unmarshal(d, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// RenewImportActivations renews the activation tokens returned by ScanImportActivations for the window.
+// The renewed activation keeps subject, import subject, type, issuer account, name and tags of the
+// current one and is valid for the same duration, starting now, but always for longer than the window.
+// The account passed in is not modified, a copy with the refreshed tokens is returned with a report
+// of every attempted renewal.
+func RenewImportActivations(account *AccountClaims, window time.Duration, signer ActivationSigner) (*AccountClaims, []*ActivationRenewal, error) {
+	return RenewImportActivationsWithResolver(account, window, signer, nil)
+}
+
+// RenewImportActivationsWithResolver is like RenewImportActivations, using the resolver to look up
+// the activations referenced by the imports. Renewed tokens are stored inline in the imports.
+func RenewImportActivationsWithResolver(account *AccountClaims, window time.Duration, signer ActivationSigner, resolver ActivationResolver) (*AccountClaims, []*ActivationRenewal, error) {
+	if account == nil {
+		return nil, nil, errors.New("account is required")
+	}
+	if signer == nil {
+		return nil, nil, errors.New("activation signer is required")
+	}
+	renewed, err := cloneAccountClaims(account)
+	if err != nil {
+		return nil, nil, err
+	}
+	var report []*ActivationRenewal
+	for _, ia := range ScanImportActivationsWithResolver(renewed, window, resolver) {
+		i := ia.Import
+		r := &ActivationRenewal{Subject: i.Subject, Account: i.Account}
+		report = append(report, r)
+		if ia.Err != nil {
+			r.Err = ia.Err
+			continue
+		}
+		cur := ia.Activation
+		r.PreviousExpires = cur.Expires
+		act := NewActivationClaims(cur.Subject)
+		act.Name = cur.Name
+		act.ImportSubject = cur.ImportSubject
+		act.ImportType = cur.ImportType
+		act.IssuerAccount = cur.IssuerAccount
+		act.Tags = cur.Tags
+		validity := time.Duration(cur.Expires-cur.IssuedAt) * time.Second
+		if cur.IssuedAt == 0 || validity <= window {
+			validity = window + time.Minute
+		}
+		act.Expires = time.Now().Add(validity).UTC().Unix()
+		token, err := signer(i.Account, act)
+		if err != nil {
+			r.Err = err
+			continue
+		}
+		// make sure the renewed token is usable by the import before replacing it
+		candidate := *i
+		candidate.Token = token
+		vr := CreateValidationResults()
+		candidate.Validate(renewed.Subject, vr)
+		if errs := vr.Errors(); len(errs) > 0 {
+			r.Err = errs[0]
+			continue
+		}
+		nact, err := DecodeActivationClaims(token)
+		if err != nil {
+			r.Err = err
+			continue
+		}
+		i.Token = token
+		r.Expires = nact.Expires
+	}
+	return renewed, report, nil
+}
@@ -0,0 +1,101 @@
+/*
+ * Copyright 2026 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwt
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nkeys"
+)
+
+func TestScanAndRenewImportActivations(t *testing.T) {
+	ekp := createAccountNKey(t)
+	exporter := publicKey(ekp, t)
+	account := NewAccountClaims(publicKey(createAccountNKey(t), t))
+
+	token := func(subj Subject, expires time.Duration, kp nkeys.KeyPair) string {
+		act := NewActivationClaims(account.Subject)
+		act.ImportSubject = subj
+		act.ImportType = Stream
+		if expires != 0 {
+			act.Expires = time.Now().Add(expires).Unix()
+		}
+		return encode(act, kp, t)
+	}
+	account.Imports.Add(&Import{Subject: "expired", Account: exporter, Type: Stream, Token: token("expired", -time.Hour, ekp)})
+	account.Imports.Add(&Import{Subject: "soon", Account: exporter, Type: Stream, Token: token("soon", time.Hour, ekp)})
+	account.Imports.Add(&Import{Subject: "later", Account: exporter, Type: Stream, Token: token("later", 48*time.Hour, ekp)})
+	account.Imports.Add(&Import{Subject: "never", Account: exporter, Type: Stream, Token: token("never", 0, ekp)})
+	account.Imports.Add(&Import{Subject: "public", Account: exporter, Type: Stream})
+
+	found := ScanImportActivations(account, 24*time.Hour)
+	AssertEquals(2, len(found), t)
+	AssertEquals(Subject("expired"), found[0].Import.Subject, t)
+	AssertTrue(found[0].Expired, t)
+	AssertEquals(Subject("soon"), found[1].Import.Subject, t)
+	AssertFalse(found[1].Expired, t)
+
+	original := account.Imports[1].Token
+	renewed, report, err := RenewImportActivations(account, 24*time.Hour, func(exp string, act *ActivationClaims) (string, error) {
+		AssertEquals(exporter, exp, t)
+		return act.Encode(ekp)
+	})
+	AssertNoError(err, t)
+	AssertEquals(2, len(report), t)
+	for _, r := range report {
+		AssertNoError(r.Err, t)
+		AssertTrue(r.Expires > time.Now().Unix(), t)
+		AssertTrue(r.Expires > r.PreviousExpires, t)
+	}
+	// the original account is not modified
+	AssertEquals(original, account.Imports[1].Token, t)
+	AssertEquals(0, len(ScanImportActivations(renewed, 24*time.Hour)), t)
+	vr := CreateValidationResults()
+	renewed.Validate(vr)
+	AssertTrue(vr.IsEmpty(), t)
+}
+
+func TestRenewImportActivations_Failures(t *testing.T) {
+	ekp := createAccountNKey(t)
+	account := NewAccountClaims(publicKey(createAccountNKey(t), t))
+	act := NewActivationClaims(account.Subject)
+	act.ImportSubject = "foo"
+	act.ImportType = Stream
+	act.Expires = time.Now().Add(time.Minute).Unix()
+	account.Imports.Add(&Import{Subject: "foo", Account: publicKey(ekp, t), Type: Stream, Token: encode(act, ekp, t)})
+	account.Imports.Add(&Import{Subject: "bar", Account: publicKey(ekp, t), Type: Stream, Token: "garbage"})
+
+	// the signer fails
+	renewed, report, err := RenewImportActivations(account, time.Hour, func(string, *ActivationClaims) (string, error) {
+		return "", errors.New("no key")
+	})
+	AssertNoError(err, t)
+	AssertEquals(2, len(report), t)
+	AssertTrue(report[0].Err != nil && report[1].Err != nil, t)
+	AssertEquals(account.Imports[0].Token, renewed.Imports[0].Token, t)
+
+	// signed by the wrong account
+	_, report, err = RenewImportActivations(account, time.Hour, func(_ string, a *ActivationClaims) (string, error) {
+		return a.Encode(createAccountNKey(t))
+	})
+	AssertNoError(err, t)
+	AssertTrue(report[0].Err != nil, t)
+
+	_, _, err = RenewImportActivations(account, time.Hour, nil)
+	AssertTrue(err != nil, t)
+}
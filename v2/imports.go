@@ -15,6 +15,10 @@
 
 package jwt
 
+import (
+	"time"
+)
+
 // Import describes a mapping from another account into this one
 type Import struct {
 	Name string `json:"name,omitempty"`
@@ -58,8 +62,45 @@ func (i *Import) GetTo() string {
 	return string(i.To)
 }
 
+// remoteSubject returns the subject of the import as exported by the exporting account
+func (i *Import) remoteSubject() Subject {
+	if i.IsService() && i.To != "" {
+		return i.To
+	}
+	return i.Subject
+}
+
+// localSubject returns the subject of the import as seen in the importing account
+func (i *Import) localSubject() Subject {
+	if i.IsService() && i.To != "" {
+		return i.Subject
+	}
+	if i.LocalSubject != "" {
+		return i.LocalSubject.ToSubject()
+	}
+	if i.To != "" {
+		return i.To
+	}
+	return i.Subject
+}
+
+// ResolveActivation returns the activation referenced by Token. If resolver is nil,
+// Token needs to be an inline activation JWT.
+func (i *Import) ResolveActivation(resolver ActivationResolver) (*ActivationClaims, error) {
+	if resolver == nil {
+		return DecodeActivationClaims(i.Token)
+	}
+	return resolver.Resolve(i.Token)
+}
+
 // Validate checks if an import is valid for the wrapping account
 func (i *Import) Validate(actPubKey string, vr *ValidationResults) {
+	i.ValidateWithResolver(actPubKey, nil, vr)
+}
+
+// ValidateWithResolver checks if an import is valid for the wrapping account,
+// using the resolver to look up the activation referenced by Token
+func (i *Import) ValidateWithResolver(actPubKey string, resolver ActivationResolver, vr *ValidationResults) {
 	if i == nil {
 		vr.AddError("null import is not allowed")
 		return
@@ -94,7 +135,7 @@ func (i *Import) Validate(actPubKey string, vr *ValidationResults) {
 
 	if i.Token != "" {
 		var err error
-		act, err = DecodeActivationClaims(i.Token)
+		act, err = i.ResolveActivation(resolver)
 		if err != nil {
 			vr.AddError("import %q contains an invalid activation token", i.Subject)
 		}
@@ -121,13 +162,96 @@ func (i *Import) Validate(actPubKey string, vr *ValidationResults) {
 	}
 }
 
+// ValidateAgainst checks the import of the account actPubKey against the claims of the exporting account.
+// It checks that a matching export of the same type exists, that an activation token is present and valid
+// if the export requires one, that the activation is not revoked by the export and that the account
+// token position of the export, if set, contains the importing account.
+func (i *Import) ValidateAgainst(actPubKey string, exporter *AccountClaims, vr *ValidationResults) {
+	i.validateAgainst(actPubKey, exporter, nil, nil, vr)
+}
+
+// ValidateAgainstWithResolver is like ValidateAgainst, using the resolver to look up the activation
+func (i *Import) ValidateAgainstWithResolver(actPubKey string, exporter *AccountClaims, resolver ActivationResolver, vr *ValidationResults) {
+	i.validateAgainst(actPubKey, exporter, nil, resolver, vr)
+}
+
+// validateAgainst uses exports to look up the exports of the exporting account, it is built if nil
+func (i *Import) validateAgainst(actPubKey string, exporter *AccountClaims, exports *exportIndex, resolver ActivationResolver, vr *ValidationResults) {
+	if i == nil {
+		vr.AddError("null import is not allowed")
+		return
+	}
+	if exporter == nil {
+		vr.AddError("exporting account for import %q is not specified", i.Subject)
+		return
+	}
+	if i.Account != exporter.Subject {
+		vr.AddError("import %q is from account %q, not %q", i.Subject, i.Account, exporter.Subject)
+		return
+	}
+	if exports == nil {
+		exports = newExportIndex(exporter.Exports)
+	}
+	subj := i.remoteSubject()
+	export := exports.findImport(i)
+	if export == nil {
+		if e := exports.find(subj, Unknown); e != nil {
+			vr.AddError("import %q is of type %s but account %q exports it as %s", i.Subject, i.Type, exporter.Subject, e.Type)
+			return
+		}
+		vr.AddError("import %q has no matching %s export in account %q", i.Subject, i.Type, exporter.Subject)
+		return
+	}
+	if export.AccountTokenPosition > 0 {
+		if pk, err := export.ImporterFromSubject(subj); err != nil || pk != actPubKey {
+			vr.AddError("import %q needs to contain the importing account %q at token position %d of export %q",
+				i.Subject, actPubKey, export.AccountTokenPosition, export.Subject)
+		}
+	}
+	if i.Token == "" {
+		if export.TokenReq {
+			vr.AddError("import %q requires an activation token from account %q", i.Subject, exporter.Subject)
+		}
+		return
+	}
+	act, err := i.ResolveActivation(resolver)
+	if err != nil {
+		vr.AddError("import %q contains an invalid activation token: %v", i.Subject, err)
+		return
+	}
+	if act.Subject != actPubKey {
+		vr.AddError("activation token for import %q is issued to %q", i.Subject, act.Subject)
+	}
+	if !exporter.DidSign(act) {
+		vr.AddError("activation token for import %q is not signed by account %q", i.Subject, exporter.Subject)
+	}
+	if act.ImportType != i.Type {
+		vr.AddError("mismatch between token import type %s and type of import %s", act.ImportType, i.Type)
+	}
+	if !subj.IsContainedIn(act.ImportSubject) {
+		vr.AddError("activation token import subject %q doesn't match import %q", act.ImportSubject, i.Subject)
+	}
+	if act.Expires > 0 && time.Now().Unix() > act.Expires {
+		vr.AddTimeCheck("activation token for import %q is expired", i.Subject)
+	}
+	if export.IsClaimRevoked(act) {
+		vr.AddError("activation token for import %q is revoked by account %q", i.Subject, exporter.Subject)
+	}
+}
+
 // Imports is a list of import structs
 type Imports []*Import
 
 // Validate checks if an import is valid for the wrapping account
 func (i *Imports) Validate(acctPubKey string, vr *ValidationResults) {
+	i.ValidateWithResolver(acctPubKey, nil, vr)
+}
+
+// ValidateWithResolver checks if the imports are valid for the wrapping account,
+// using the resolver to look up the activations referenced by the imports
+func (i *Imports) ValidateWithResolver(acctPubKey string, resolver ActivationResolver, vr *ValidationResults) {
 	// Group subjects by account to check for overlaps only within the same account
-	subsByAcct := make(map[string]map[Subject]struct{}, len(*i))
+	subsByAcct := make(map[string]*Sublist, len(*i))
 	for _, v := range *i {
 		if v == nil {
 			vr.AddError("null import is not allowed")
@@ -141,21 +265,28 @@ func (i *Imports) Validate(acctPubKey string, vr *ValidationResults) {
 			if sub == "" {
 				sub = v.Subject
 			}
+			sl, ok := subsByAcct[v.Account]
+			if !ok {
+				sl = NewSublist()
+				subsByAcct[v.Account] = sl
+			}
 			// Check for overlapping subjects only within the same account
-			for subOther := range subsByAcct[v.Account] {
-				if sub.IsContainedIn(subOther) || subOther.IsContainedIn(sub) {
-					vr.AddError("overlapping subject namespace for %q and %q in same account %q", sub, subOther, v.Account)
+			duplicate := false
+			for _, o := range sl.Overlaps(sub) {
+				subOther := o.(Subject)
+				if subOther == sub {
+					duplicate = true
 				}
+				vr.AddError("overlapping subject namespace for %q and %q in same account %q on %q",
+					sub, subOther, v.Account, sub.Intersection(subOther))
 			}
-			if subsByAcct[v.Account] == nil {
-				subsByAcct[v.Account] = make(map[Subject]struct{}, len(*i))
-			}
-			if _, ok := subsByAcct[v.Account][sub]; ok {
+			if duplicate {
 				vr.AddError("overlapping subject namespace for %q in account %q", sub, v.Account)
+			} else {
+				sl.Insert(sub, sub)
 			}
-			subsByAcct[v.Account][sub] = struct{}{}
 		}
-		v.Validate(acctPubKey, vr)
+		v.ValidateWithResolver(acctPubKey, resolver, vr)
 	}
 }
 
@@ -556,6 +556,14 @@ func TestImports_Validate_ServiceConflicts(t *testing.T) {
 			},
 			valid: false, // nats-server does not allow this today, hence it should be rejected here as well.
 		},
+		{
+			test: "Partially overlapping subjects from same account",
+			imports: []*Import{
+				{Account: acctA, Subject: "a.*.c", Type: Service},
+				{Account: acctA, Subject: "a.b.*", Type: Service},
+			},
+			valid: false,
+		},
 		{
 			test: "Same subject from different accounts",
 			imports: []*Import{
@@ -634,3 +642,53 @@ func TestImportAllowTrace(t *testing.T) {
 		t.Fatalf("validation should have been ok, got %+v", vr.Issues)
 	}
 }
+
+func TestImport_ValidateAgainst(t *testing.T) {
+	akp := createAccountNKey(t)
+	exporter := NewAccountClaims(publicKey(akp, t))
+	importer := publicKey(createAccountNKey(t), t)
+	exporter.Exports.Add(&Export{Subject: "public.>", Type: Stream})
+	exporter.Exports.Add(&Export{Subject: "private", Type: Service, TokenReq: true})
+	exporter.Exports.Add(&Export{Subject: "tenant.*.req", Type: Service, AccountTokenPosition: 2})
+
+	validate := func(i *Import) *ValidationResults {
+		vr := CreateValidationResults()
+		i.ValidateAgainst(importer, exporter, vr)
+		return vr
+	}
+
+	AssertTrue(validate(&Import{Subject: "public.a", Account: exporter.Subject, Type: Stream}).IsEmpty(), t)
+	AssertTrue(validate(&Import{Subject: "public.a", Account: exporter.Subject, Type: Service}).IsBlocking(false), t)
+	AssertTrue(validate(&Import{Subject: "missing", Account: exporter.Subject, Type: Stream}).IsBlocking(false), t)
+	AssertTrue(validate(&Import{Subject: "public.a", Account: importer, Type: Stream}).IsBlocking(false), t)
+
+	// token position needs to contain the importer
+	AssertTrue(validate(&Import{Subject: Subject("tenant." + importer + ".req"), Account: exporter.Subject, Type: Service}).IsEmpty(), t)
+	AssertTrue(validate(&Import{Subject: "tenant.other.req", Account: exporter.Subject, Type: Service}).IsBlocking(false), t)
+
+	// token required
+	i := &Import{Subject: "private", Account: exporter.Subject, Type: Service}
+	AssertTrue(validate(i).IsBlocking(false), t)
+	act := NewActivationClaims(importer)
+	act.ImportSubject = "private"
+	act.ImportType = Service
+	i.Token = encode(act, akp, t)
+	AssertTrue(validate(i).IsEmpty(), t)
+
+	// revoked by the export
+	exporter.Exports[1].RevokeAt(importer, time.Now().Add(time.Minute))
+	AssertTrue(validate(i).IsBlocking(false), t)
+	exporter.Exports[1].ClearRevocation(importer)
+
+	// expired tokens are reported as time check
+	act.Expires = time.Now().Add(-time.Hour).Unix()
+	i.Token = encode(act, akp, t)
+	vr := validate(i)
+	AssertFalse(vr.IsBlocking(false), t)
+	AssertTrue(vr.IsBlocking(true), t)
+
+	// token signed by another account
+	act.Expires = 0
+	i.Token = encode(act, createAccountNKey(t), t)
+	AssertTrue(validate(i).IsBlocking(false), t)
+}
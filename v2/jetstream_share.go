@@ -0,0 +1,299 @@
+/*
+ * Copyright 2026 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/nats-io/nkeys"
+)
+
+const (
+	jsAPIPrefix = "$JS.API"
+	jsAckPrefix = "$JS.ACK"
+	jsFCPrefix  = "$JS.FC"
+)
+
+// JetStreamDelivery is the way messages of a shared consumer are delivered
+type JetStreamDelivery string
+
+const (
+	// PullDelivery consumers deliver messages in response to next message requests
+	PullDelivery JetStreamDelivery = "pull"
+	// PushDelivery consumers deliver messages to a deliver subject
+	PushDelivery JetStreamDelivery = "push"
+)
+
+// JetStreamShare holds the exports of the account owning a stream and the matching
+// imports of the account using it
+type JetStreamShare struct {
+	Exporter string
+	Importer string
+	Exports  Exports
+	Imports  Imports
+}
+
+// ConsumerShare describes a consumer shared with another account
+type ConsumerShare struct {
+	Stream   string
+	Consumer string
+	Delivery JetStreamDelivery
+	// DeliverSubject is the deliver subject of a push consumer
+	DeliverSubject Subject
+	// APIPrefix optionally imports the JetStream API subjects under this prefix instead of $JS.API,
+	// so that they don't collide with the JetStream API of the importing account
+	APIPrefix Subject
+	// TokenReq makes the exports private, the imports then require activation tokens
+	TokenReq bool
+}
+
+// StreamSourceShare describes a stream mirrored or sourced by a stream in another account
+type StreamSourceShare struct {
+	Stream string
+	// APIPrefix is the prefix of the JetStream API of the exporting account in the importing account,
+	// it is the external API prefix of the mirror or source, e.g. JS.<account>.API
+	APIPrefix Subject
+	// DeliverPrefix is the prefix of the deliver subjects of the consumers created for the mirror or source
+	DeliverPrefix Subject
+	// TokenReq makes the exports private, the imports then require activation tokens
+	TokenReq bool
+}
+
+func validJetStreamName(kind string, name string) error {
+	if name == "" {
+		return fmt.Errorf("%s name is required", kind)
+	}
+	if strings.ContainsAny(name, ".*> \t\r\n") {
+		return fmt.Errorf("%s name %q is not valid", kind, name)
+	}
+	return nil
+}
+
+func validPrefix(kind string, prefix Subject) error {
+	vr := CreateValidationResults()
+	prefix.Validate(vr)
+	if !vr.IsEmpty() {
+		return fmt.Errorf("%s %s", kind, vr.Issues[0].Description)
+	}
+	if prefix.HasWildCards() {
+		return fmt.Errorf("%s %q can't contain wildcards", kind, prefix)
+	}
+	return nil
+}
+
+func newJetStreamShare(exporter string, importer string) (*JetStreamShare, error) {
+	if !nkeys.IsValidPublicAccountKey(exporter) {
+		return nil, fmt.Errorf("%q is not an account public key", exporter)
+	}
+	if !nkeys.IsValidPublicAccountKey(importer) {
+		return nil, fmt.Errorf("%q is not an account public key", importer)
+	}
+	if exporter == importer {
+		return nil, errors.New("exporting and importing account need to be different")
+	}
+	return &JetStreamShare{Exporter: exporter, Importer: importer}, nil
+}
+
+// add adds an export and the matching import. Service imports of JetStream API subjects
+// are renamed to apiPrefix if set.
+func (j *JetStreamShare) add(name string, subject Subject, typ ExportType, rt ResponseType, tokenReq bool, apiPrefix Subject) {
+	e := &Export{Name: name, Subject: subject, Type: typ, ResponseType: rt, TokenReq: tokenReq}
+	i := &Import{Name: name, Subject: subject, Account: j.Exporter, Type: typ}
+	if apiPrefix != "" && strings.HasPrefix(string(subject), jsAPIPrefix+".") {
+		i.LocalSubject = RenamingSubject(string(apiPrefix) + strings.TrimPrefix(string(subject), jsAPIPrefix))
+	}
+	j.Exports.Add(e)
+	j.Imports.Add(i)
+}
+
+// Template creates the exports and imports needed to share the consumer of the exporting
+// account with the importing account
+func (c *ConsumerShare) Template(exporter string, importer string) (*JetStreamShare, error) {
+	if err := validJetStreamName("stream", c.Stream); err != nil {
+		return nil, err
+	}
+	if err := validJetStreamName("consumer", c.Consumer); err != nil {
+		return nil, err
+	}
+	if c.APIPrefix != "" {
+		if err := validPrefix("api prefix", c.APIPrefix); err != nil {
+			return nil, err
+		}
+	}
+	j, err := newJetStreamShare(exporter, importer)
+	if err != nil {
+		return nil, err
+	}
+	sc := c.Stream + "." + c.Consumer
+	name := "consumer " + sc
+	j.add(name, Subject(jsAPIPrefix+".CONSUMER.INFO."+sc), Service, ResponseTypeSingleton, c.TokenReq, c.APIPrefix)
+	switch c.Delivery {
+	case PullDelivery:
+		if c.DeliverSubject != "" {
+			return nil, errors.New("pull consumers don't have a deliver subject")
+		}
+		j.add(name, Subject(jsAPIPrefix+".CONSUMER.MSG.NEXT."+sc), Service, ResponseTypeStream, c.TokenReq, c.APIPrefix)
+	case PushDelivery:
+		if c.DeliverSubject == "" {
+			return nil, errors.New("push consumers require a deliver subject")
+		}
+		if err := validPrefix("deliver subject", c.DeliverSubject); err != nil {
+			return nil, err
+		}
+		j.add(name, c.DeliverSubject, Stream, "", c.TokenReq, "")
+		j.add(name, Subject(jsFCPrefix+"."+c.Stream+".>"), Service, ResponseTypeSingleton, c.TokenReq, "")
+	default:
+		return nil, fmt.Errorf("unknown delivery %q", c.Delivery)
+	}
+	j.add(name, Subject(jsAckPrefix+"."+sc+".>"), Service, ResponseTypeSingleton, c.TokenReq, "")
+	return j, nil
+}
+
+// Template creates the exports and imports needed to mirror or source the stream of the exporting
+// account into a stream of the importing account
+func (s *StreamSourceShare) Template(exporter string, importer string) (*JetStreamShare, error) {
+	if err := validJetStreamName("stream", s.Stream); err != nil {
+		return nil, err
+	}
+	if s.APIPrefix == "" {
+		return nil, errors.New("api prefix is required")
+	}
+	if err := validPrefix("api prefix", s.APIPrefix); err != nil {
+		return nil, err
+	}
+	if s.DeliverPrefix == "" {
+		return nil, errors.New("deliver prefix is required")
+	}
+	if err := validPrefix("deliver prefix", s.DeliverPrefix); err != nil {
+		return nil, err
+	}
+	j, err := newJetStreamShare(exporter, importer)
+	if err != nil {
+		return nil, err
+	}
+	name := "stream " + s.Stream
+	j.add(name, Subject(jsAPIPrefix+".CONSUMER.CREATE."+s.Stream), Service, ResponseTypeSingleton, s.TokenReq, s.APIPrefix)
+	j.add(name, Subject(jsAPIPrefix+".CONSUMER.CREATE."+s.Stream+".>"), Service, ResponseTypeSingleton, s.TokenReq, s.APIPrefix)
+	j.add(name, Subject(string(s.DeliverPrefix)+".>"), Stream, "", s.TokenReq, "")
+	j.add(name, Subject(jsFCPrefix+"."+s.Stream+".>"), Service, ResponseTypeSingleton, s.TokenReq, "")
+	return j, nil
+}
+
+// checkJetStreamExport checks that the export used for the JetStream subject has the response type the server expects
+func checkJetStreamExport(subject Subject, e *Export, vr *ValidationResults) {
+	subj := string(subject)
+	switch {
+	case strings.HasPrefix(subj, jsAPIPrefix+".CONSUMER.MSG.NEXT."):
+		if !e.IsService() || !e.IsStreamResponse() {
+			vr.AddError("export %q needs to be a service with response type %q", e.Subject, ResponseTypeStream)
+		}
+	case strings.HasPrefix(subj, jsAPIPrefix+"."), strings.HasPrefix(subj, jsAckPrefix+"."), strings.HasPrefix(subj, jsFCPrefix+"."):
+		if !e.IsService() || !e.IsSingleResponse() {
+			vr.AddError("export %q needs to be a service with response type %q", e.Subject, ResponseTypeSingleton)
+		}
+	}
+}
+
+// Validate checks that every import resolves to an export of the share with a matching type
+// and that the JetStream subjects use the response types the server expects.
+// Imports of exports requiring tokens are only valid once Apply issued the activations.
+func (j *JetStreamShare) Validate(vr *ValidationResults) {
+	j.Exports.Validate(vr)
+	j.Imports.Validate(j.Importer, vr)
+	for _, e := range j.Exports {
+		checkJetStreamExport(e.Subject, e, vr)
+	}
+	exports := newExportIndex(j.Exports)
+	for _, i := range j.Imports {
+		if i.Account != j.Exporter {
+			vr.AddError("import %q is from account %q, not the exporting account %q", i.Subject, i.Account, j.Exporter)
+			continue
+		}
+		e := exports.findImport(i)
+		if e == nil {
+			vr.AddError("import %q has no matching %s export", i.Subject, i.Type)
+			continue
+		}
+		if e.TokenReq && i.Token == "" {
+			vr.AddError("import %q requires an activation token", i.Subject)
+		}
+		if strings.HasPrefix(string(i.localSubject()), jsAckPrefix+".") != strings.HasPrefix(string(e.Subject), jsAckPrefix+".") {
+			vr.AddError("import %q can't rename the acknowledgement subject %q", i.Subject, e.Subject)
+		}
+	}
+}
+
+// Apply adds the exports to the exporting account and the imports to the importing account.
+// Exports of the exporting account containing an export of the share are reused, they need the
+// response type the server expects for the JetStream subject. If exports require tokens, the
+// activations are issued with signer, the exporting account or one of its signing keys.
+// Neither account is modified if the merged exports or the imports are not consistent.
+func (j *JetStreamShare) Apply(exporter *AccountClaims, importer *AccountClaims, signer nkeys.KeyPair, opts *ActivationOptions) error {
+	if exporter == nil || exporter.Subject != j.Exporter {
+		return fmt.Errorf("exporting account %q is required", j.Exporter)
+	}
+	if importer == nil || importer.Subject != j.Importer {
+		return fmt.Errorf("importing account %q is required", j.Importer)
+	}
+	exports := append(Exports{}, exporter.Exports...)
+	var imports Imports
+	for idx, e := range j.Exports {
+		cur := newExportIndex(exports).find(e.Subject, e.Type)
+		if cur == nil {
+			cur = &Export{}
+			*cur = *e
+			exports.Add(cur)
+		}
+		i := &Import{}
+		*i = *j.Imports[idx]
+		if cur.TokenReq {
+			if signer == nil {
+				return fmt.Errorf("export %q requires an activation token, a signer is required", cur.Subject)
+			}
+			tmp := *exporter
+			tmp.Exports = exports
+			token, err := cur.IssueActivation(&tmp, j.Importer, signer, opts)
+			if err != nil {
+				return err
+			}
+			i.Token = token
+		}
+		imports.Add(i)
+	}
+	vr := CreateValidationResults()
+	check := &JetStreamShare{Exporter: j.Exporter, Importer: j.Importer, Exports: j.Exports, Imports: imports}
+	check.Validate(vr)
+	exports.Validate(vr)
+	merged := newExportIndex(exports)
+	for _, e := range j.Exports {
+		if cur := merged.find(e.Subject, e.Type); cur != nil {
+			checkJetStreamExport(e.Subject, cur, vr)
+		}
+	}
+	updated := *exporter
+	updated.Exports = exports
+	for _, i := range imports {
+		i.ValidateAgainst(j.Importer, &updated, vr)
+	}
+	if errs := vr.Errors(); len(errs) > 0 {
+		return errs[0]
+	}
+	exporter.Exports = exports
+	importer.Imports.Add(imports...)
+	return nil
+}
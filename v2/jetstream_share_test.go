@@ -0,0 +1,204 @@
+/*
+ * Copyright 2026 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwt
+
+import (
+	"strings"
+	"testing"
+)
+
+func exportFor(j *JetStreamShare, subject Subject) *Export {
+	for _, e := range j.Exports {
+		if e.Subject == subject {
+			return e
+		}
+	}
+	return nil
+}
+
+func TestConsumerShare_Pull(t *testing.T) {
+	a := publicKey(createAccountNKey(t), t)
+	b := publicKey(createAccountNKey(t), t)
+	c := &ConsumerShare{Stream: "ORDERS", Consumer: "pull", Delivery: PullDelivery, APIPrefix: "JS.A.API"}
+	j, err := c.Template(a, b)
+	AssertNoError(err, t)
+	AssertEquals(3, len(j.Exports), t)
+	AssertEquals(3, len(j.Imports), t)
+
+	next := exportFor(j, "$JS.API.CONSUMER.MSG.NEXT.ORDERS.pull")
+	AssertTrue(next != nil && next.IsStreamResponse(), t)
+	AssertTrue(exportFor(j, "$JS.API.CONSUMER.INFO.ORDERS.pull") != nil, t)
+	AssertTrue(exportFor(j, "$JS.ACK.ORDERS.pull.>") != nil, t)
+	for _, i := range j.Imports {
+		AssertEquals(a, i.Account, t)
+		switch i.Subject {
+		case "$JS.API.CONSUMER.MSG.NEXT.ORDERS.pull":
+			AssertEquals(RenamingSubject("JS.A.API.CONSUMER.MSG.NEXT.ORDERS.pull"), i.LocalSubject, t)
+		case "$JS.ACK.ORDERS.pull.>":
+			AssertEquals(RenamingSubject(""), i.LocalSubject, t)
+		}
+	}
+
+	vr := CreateValidationResults()
+	j.Validate(vr)
+	AssertTrue(vr.IsEmpty(), t)
+
+	// a pull request export needs to stream responses
+	next.ResponseType = ResponseTypeSingleton
+	vr = CreateValidationResults()
+	j.Validate(vr)
+	AssertTrue(hasIssue(vr, true, `needs to be a service with response type "Stream"`), t)
+}
+
+func TestConsumerShare_Push(t *testing.T) {
+	a := publicKey(createAccountNKey(t), t)
+	b := publicKey(createAccountNKey(t), t)
+	c := &ConsumerShare{Stream: "ORDERS", Consumer: "push", Delivery: PushDelivery, DeliverSubject: "deliver.orders"}
+	j, err := c.Template(a, b)
+	AssertNoError(err, t)
+	deliver := exportFor(j, "deliver.orders")
+	AssertTrue(deliver != nil && deliver.IsStream(), t)
+	AssertTrue(exportFor(j, "$JS.FC.ORDERS.>") != nil, t)
+	AssertTrue(exportFor(j, "$JS.ACK.ORDERS.push.>") != nil, t)
+	AssertTrue(exportFor(j, "$JS.API.CONSUMER.MSG.NEXT.ORDERS.push") == nil, t)
+	vr := CreateValidationResults()
+	j.Validate(vr)
+	AssertTrue(vr.IsEmpty(), t)
+
+	// an import not matching the exports is inconsistent
+	j.Imports[0].Subject = "$JS.API.CONSUMER.INFO.ORDERS.other"
+	vr = CreateValidationResults()
+	j.Validate(vr)
+	AssertTrue(hasIssue(vr, true, "has no matching service export"), t)
+}
+
+func TestConsumerShare_Invalid(t *testing.T) {
+	a := publicKey(createAccountNKey(t), t)
+	b := publicKey(createAccountNKey(t), t)
+	for _, c := range []*ConsumerShare{
+		{Stream: "", Consumer: "c", Delivery: PullDelivery},
+		{Stream: "S.X", Consumer: "c", Delivery: PullDelivery},
+		{Stream: "S", Consumer: "c*", Delivery: PullDelivery},
+		{Stream: "S", Consumer: "c", Delivery: "other"},
+		{Stream: "S", Consumer: "c", Delivery: PushDelivery},
+		{Stream: "S", Consumer: "c", Delivery: PushDelivery, DeliverSubject: "deliver.*"},
+		{Stream: "S", Consumer: "c", Delivery: PullDelivery, DeliverSubject: "deliver"},
+		{Stream: "S", Consumer: "c", Delivery: PullDelivery, APIPrefix: "JS.>"},
+	} {
+		_, err := c.Template(a, b)
+		AssertTrue(err != nil, t)
+	}
+	_, err := (&ConsumerShare{Stream: "S", Consumer: "c", Delivery: PullDelivery}).Template(a, a)
+	AssertTrue(err != nil, t)
+	_, err = (&ConsumerShare{Stream: "S", Consumer: "c", Delivery: PullDelivery}).Template(a, "bad")
+	AssertTrue(err != nil, t)
+}
+
+func TestStreamSourceShare(t *testing.T) {
+	a := publicKey(createAccountNKey(t), t)
+	b := publicKey(createAccountNKey(t), t)
+	s := &StreamSourceShare{Stream: "ORDERS", APIPrefix: "JS.A.API", DeliverPrefix: "deliver.a"}
+	j, err := s.Template(a, b)
+	AssertNoError(err, t)
+	AssertEquals(4, len(j.Exports), t)
+	AssertTrue(exportFor(j, "$JS.API.CONSUMER.CREATE.ORDERS") != nil, t)
+	AssertTrue(exportFor(j, "$JS.API.CONSUMER.CREATE.ORDERS.>") != nil, t)
+	AssertTrue(exportFor(j, "deliver.a.>").IsStream(), t)
+	AssertTrue(exportFor(j, "$JS.FC.ORDERS.>") != nil, t)
+	AssertEquals(RenamingSubject("JS.A.API.CONSUMER.CREATE.ORDERS.>"), j.Imports[1].LocalSubject, t)
+	vr := CreateValidationResults()
+	j.Validate(vr)
+	AssertTrue(vr.IsEmpty(), t)
+
+	_, err = (&StreamSourceShare{Stream: "ORDERS", DeliverPrefix: "deliver.a"}).Template(a, b)
+	AssertTrue(err != nil, t)
+	_, err = (&StreamSourceShare{Stream: "ORDERS", APIPrefix: "JS.A.API"}).Template(a, b)
+	AssertTrue(err != nil, t)
+}
+
+func TestJetStreamShare_Apply(t *testing.T) {
+	akp := createAccountNKey(t)
+	exporter := NewAccountClaims(publicKey(akp, t))
+	importer := NewAccountClaims(publicKey(createAccountNKey(t), t))
+	// an existing export is reused
+	exporter.Exports.Add(&Export{Subject: "$JS.ACK.ORDERS.pull.>", Type: Service, TokenReq: true})
+
+	c := &ConsumerShare{Stream: "ORDERS", Consumer: "pull", Delivery: PullDelivery, APIPrefix: "JS.A.API", TokenReq: true}
+	j, err := c.Template(exporter.Subject, importer.Subject)
+	AssertNoError(err, t)
+
+	// private exports are not valid without tokens
+	vr := CreateValidationResults()
+	j.Validate(vr)
+	AssertTrue(hasIssue(vr, true, "requires an activation token"), t)
+	AssertTrue(j.Apply(exporter, importer, nil, nil) != nil, t)
+	AssertEquals(1, len(exporter.Exports), t)
+	AssertEquals(0, len(importer.Imports), t)
+
+	AssertNoError(j.Apply(exporter, importer, akp, nil), t)
+	AssertEquals(3, len(exporter.Exports), t)
+	AssertEquals(3, len(importer.Imports), t)
+	for _, i := range importer.Imports {
+		AssertTrue(i.Token != "", t)
+	}
+	report := NewAccountGraph(exporter, importer).Validate()
+	AssertTrue(report[exporter.Subject].IsEmpty(), t)
+	AssertTrue(report[importer.Subject].IsEmpty(), t)
+
+	vr = CreateValidationResults()
+	importer.Validate(vr)
+	AssertTrue(vr.IsEmpty(), t)
+	vr = CreateValidationResults()
+	exporter.Validate(vr)
+	AssertTrue(vr.IsEmpty(), t)
+}
+
+func TestJetStreamShare_ApplyExistingExports(t *testing.T) {
+	akp := createAccountNKey(t)
+	exporter := NewAccountClaims(publicKey(akp, t))
+	importer := NewAccountClaims(publicKey(createAccountNKey(t), t))
+	// a wider existing export is reused instead of adding an overlapping one
+	exporter.Exports.Add(&Export{Subject: "$JS.ACK.>", Type: Service})
+
+	c := &ConsumerShare{Stream: "S", Consumer: "C", Delivery: PullDelivery}
+	j, err := c.Template(exporter.Subject, importer.Subject)
+	AssertNoError(err, t)
+	AssertNoError(j.Apply(exporter, importer, akp, nil), t)
+	AssertEquals(3, len(exporter.Exports), t)
+	vr := CreateValidationResults()
+	exporter.Validate(vr)
+	AssertTrue(vr.IsEmpty(), t)
+
+	// a reused export with the wrong response type is refused
+	exporter = NewAccountClaims(publicKey(akp, t))
+	importer = NewAccountClaims(publicKey(createAccountNKey(t), t))
+	exporter.Exports.Add(&Export{Subject: "$JS.API.CONSUMER.MSG.NEXT.S.C", Type: Service, ResponseType: ResponseTypeSingleton})
+	j, err = c.Template(exporter.Subject, importer.Subject)
+	AssertNoError(err, t)
+	err = j.Apply(exporter, importer, akp, nil)
+	AssertTrue(err != nil && strings.Contains(err.Error(), "needs to be a service with response type"), t)
+	AssertEquals(1, len(exporter.Exports), t)
+	AssertEquals(0, len(importer.Imports), t)
+
+	// a narrower existing export overlaps the export of the share
+	exporter = NewAccountClaims(publicKey(akp, t))
+	exporter.Exports.Add(&Export{Subject: "$JS.ACK.S.C.x", Type: Service})
+	j, err = c.Template(exporter.Subject, importer.Subject)
+	AssertNoError(err, t)
+	err = j.Apply(exporter, importer, akp, nil)
+	AssertTrue(err != nil && strings.Contains(err.Error(), "already exports"), t)
+	AssertEquals(1, len(exporter.Exports), t)
+}
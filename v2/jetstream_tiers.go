@@ -0,0 +1,165 @@
+/*
+ * Copyright 2026 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwt
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// MaxJetStreamReplicas is the highest number of replicas a stream can have
+const MaxJetStreamReplicas = 5
+
+// TierName returns the name of the JetStream tier the server uses for streams with the replicas, e.g. R3.
+// Streams without replicas use the R1 tier.
+func TierName(replicas int) string {
+	if replicas < 1 {
+		replicas = 1
+	}
+	return fmt.Sprintf("R%d", replicas)
+}
+
+// ParseTierName returns the replicas of a tier name following the server's R<replicas> convention
+func ParseTierName(name string) (int, error) {
+	if !strings.HasPrefix(name, "R") {
+		return 0, fmt.Errorf("tier name %q does not start with R", name)
+	}
+	r, err := strconv.Atoi(name[1:])
+	if err != nil || r < 1 || name[1] == '0' || name[1] == '+' {
+		return 0, fmt.Errorf("tier name %q does not end with the number of replicas", name)
+	}
+	return r, nil
+}
+
+// Tiers returns the tier names sorted by name
+func (t JetStreamTieredLimits) Tiers() []string {
+	tiers := make([]string, 0, len(t))
+	for k := range t {
+		tiers = append(tiers, k)
+	}
+	sort.Strings(tiers)
+	return tiers
+}
+
+// Validate checks the limits of every tier, tier names not following the server's naming
+// are not used by the server and tiers with limits but without storage can't hold streams
+func (t JetStreamTieredLimits) Validate(vr *ValidationResults) {
+	for _, k := range t.Tiers() {
+		l := t[k]
+		kind := fmt.Sprintf("JetStream tier %q", k)
+		l.validate(vr, kind)
+		if k == "" {
+			continue
+		}
+		if r, err := ParseTierName(k); err != nil {
+			vr.AddWarning("%s is not used by the server: %v", kind, err)
+		} else if r > MaxJetStreamReplicas {
+			vr.AddWarning("%s is not used by the server, streams can't have more than %d replicas", kind, MaxJetStreamReplicas)
+		}
+		if l.MemoryStorage == 0 && l.DiskStorage == 0 && (l.Streams != 0 || l.Consumer != 0) {
+			vr.AddWarning("%s limits streams and consumers but has neither memory nor disk storage", kind)
+		}
+	}
+}
+
+// ValidateStorage checks the storage limits of every tier, see JetStreamLimits.ValidateStorage
+func (t JetStreamTieredLimits) ValidateStorage(vr *ValidationResults) {
+	for _, k := range t.Tiers() {
+		l := t[k]
+		l.validateStorage(vr, fmt.Sprintf("JetStream tier %q", k))
+	}
+}
+
+// JetStreamLimitsFor returns the JetStream limits applying to a stream with the replicas.
+// Without tiers the flat limits apply to all streams. It returns false if JetStream is not
+// enabled for streams with the replicas.
+func (o *OperatorLimits) JetStreamLimitsFor(replicas int) (JetStreamLimits, bool) {
+	l := o.JetStreamLimits
+	if len(o.JetStreamTieredLimits) > 0 {
+		var ok bool
+		if l, ok = o.JetStreamTieredLimits[TierName(replicas)]; !ok {
+			return JetStreamLimits{}, false
+		}
+	}
+	return l, l.MemoryStorage != 0 || l.DiskStorage != 0
+}
+
+// JetStreamUsage is the JetStream resource usage of an account, or of one of its tiers
+type JetStreamUsage struct {
+	Memory    int64
+	Disk      int64
+	Streams   int64
+	Consumers int64
+}
+
+// JetStreamLimitViolation describes a usage exceeding a JetStream limit
+type JetStreamLimitViolation struct {
+	// Tier is the name of the tier, empty for the flat limits
+	Tier  string
+	Limit string
+	Usage int64
+	Max   int64
+}
+
+func (v *JetStreamLimitViolation) String() string {
+	tier := "JetStream"
+	if v.Tier != "" {
+		tier = fmt.Sprintf("JetStream tier %q", v.Tier)
+	}
+	return fmt.Sprintf("%s %s usage %d exceeds the limit %d", tier, v.Limit, v.Usage, v.Max)
+}
+
+// CheckUsage returns the limits exceeded by the usage. Storage limits of 0 disable the storage,
+// stream and consumer limits of 0 are not enforced, like NoLimit.
+func (j *JetStreamLimits) CheckUsage(tier string, u JetStreamUsage) []*JetStreamLimitViolation {
+	var r []*JetStreamLimitViolation
+	check := func(name string, usage int64, max int64, zeroLimits bool) {
+		if max == NoLimit || (max == 0 && !zeroLimits) {
+			return
+		}
+		if usage > max {
+			r = append(r, &JetStreamLimitViolation{Tier: tier, Limit: name, Usage: usage, Max: max})
+		}
+	}
+	check("memory storage", u.Memory, j.MemoryStorage, true)
+	check("disk storage", u.Disk, j.DiskStorage, true)
+	check("streams", u.Streams, j.Streams, false)
+	check("consumer", u.Consumers, j.Consumer, false)
+	return r
+}
+
+// CheckJetStreamUsage returns the limits exceeded by the usage, keyed by tier name. Without tiers
+// the usage is expected under the empty name. Usage for tiers that don't exist exceeds all limits.
+func (o *OperatorLimits) CheckJetStreamUsage(usage map[string]JetStreamUsage) []*JetStreamLimitViolation {
+	names := make([]string, 0, len(usage))
+	for k := range usage {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	var r []*JetStreamLimitViolation
+	for _, k := range names {
+		l := o.JetStreamLimits
+		if len(o.JetStreamTieredLimits) > 0 {
+			l = o.JetStreamTieredLimits[k]
+		} else if k != "" {
+			l = JetStreamLimits{}
+		}
+		r = append(r, l.CheckUsage(k, usage[k])...)
+	}
+	return r
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright 2026 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwt
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTierName(t *testing.T) {
+	AssertEquals("R1", TierName(0), t)
+	AssertEquals("R1", TierName(1), t)
+	AssertEquals("R3", TierName(3), t)
+
+	r, err := ParseTierName("R3")
+	AssertNoError(err, t)
+	AssertEquals(3, r, t)
+	for _, n := range []string{"", "R", "R0", "R03", "R+3", "R-1", "r3", "X3", "R3x"} {
+		_, err := ParseTierName(n)
+		AssertTrue(err != nil, t)
+	}
+}
+
+func TestJetStreamTieredLimits_Validate(t *testing.T) {
+	tiers := JetStreamTieredLimits{
+		"R1":     {DiskStorage: 1024, Streams: NoLimit, Consumer: NoLimit},
+		"R3":     {Streams: 10},
+		"R7":     {DiskStorage: 1024},
+		"large":  {DiskStorage: 1024},
+		"replic": {MemoryStorage: -5},
+	}
+	vr := CreateValidationResults()
+	tiers.Validate(vr)
+	AssertTrue(hasIssue(vr, false, `JetStream tier "R3" limits streams and consumers but has neither memory nor disk storage`), t)
+	AssertTrue(hasIssue(vr, false, `JetStream tier "R7" is not used by the server, streams can't have more than 5 replicas`), t)
+	AssertTrue(hasIssue(vr, false, `JetStream tier "large" is not used by the server`), t)
+	AssertTrue(hasIssue(vr, true, `JetStream tier "replic" memory storage limit -5 is invalid`), t)
+	AssertFalse(hasIssue(vr, false, `"R1"`), t)
+	AssertEquals("R1,R3,R7,large,replic", strings.Join(tiers.Tiers(), ","), t)
+}
+
+func TestOperatorLimits_JetStreamLimitsFor(t *testing.T) {
+	o := OperatorLimits{}
+	_, ok := o.JetStreamLimitsFor(1)
+	AssertFalse(ok, t)
+
+	o.JetStreamLimits = JetStreamLimits{DiskStorage: 1024}
+	l, ok := o.JetStreamLimitsFor(3)
+	AssertTrue(ok, t)
+	AssertEquals(int64(1024), l.DiskStorage, t)
+
+	o.JetStreamLimits = JetStreamLimits{}
+	o.JetStreamTieredLimits = JetStreamTieredLimits{
+		"R1": {DiskStorage: 1024},
+		"R3": {MemoryStorage: 512},
+	}
+	l, ok = o.JetStreamLimitsFor(0)
+	AssertTrue(ok, t)
+	AssertEquals(int64(1024), l.DiskStorage, t)
+	l, ok = o.JetStreamLimitsFor(3)
+	AssertTrue(ok, t)
+	AssertEquals(int64(512), l.MemoryStorage, t)
+	_, ok = o.JetStreamLimitsFor(5)
+	AssertFalse(ok, t)
+}
+
+func TestOperatorLimits_CheckJetStreamUsage(t *testing.T) {
+	o := OperatorLimits{JetStreamLimits: JetStreamLimits{MemoryStorage: 100, DiskStorage: NoLimit, Streams: 2}}
+	v := o.CheckJetStreamUsage(map[string]JetStreamUsage{"": {Memory: 50, Disk: 1 << 30, Streams: 2, Consumers: 100}})
+	AssertEquals(0, len(v), t)
+	v = o.CheckJetStreamUsage(map[string]JetStreamUsage{"": {Memory: 150, Streams: 3}})
+	AssertEquals(2, len(v), t)
+	AssertEquals("JetStream memory storage usage 150 exceeds the limit 100", v[0].String(), t)
+	AssertEquals("streams", v[1].Limit, t)
+
+	o = OperatorLimits{JetStreamTieredLimits: JetStreamTieredLimits{
+		"R1": {DiskStorage: 100, Consumer: 1},
+		"R3": {DiskStorage: 300},
+	}}
+	v = o.CheckJetStreamUsage(map[string]JetStreamUsage{
+		"R1": {Disk: 10, Consumers: 2},
+		"R3": {Disk: 400, Memory: 1},
+		"R5": {Disk: 1},
+	})
+	AssertEquals(4, len(v), t)
+	AssertEquals(`JetStream tier "R1" consumer usage 2 exceeds the limit 1`, v[0].String(), t)
+	AssertEquals("R3", v[1].Tier, t)
+	AssertEquals("memory storage", v[1].Limit, t)
+	AssertEquals("disk storage", v[2].Limit, t)
+	AssertEquals("R5", v[3].Tier, t)
+}
@@ -0,0 +1,186 @@
+/*
+ * Copyright 2026 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwt
+
+import (
+	"sort"
+)
+
+// LimitChangeKind classifies the change of a limit
+type LimitChangeKind string
+
+const (
+	LimitUnchanged LimitChangeKind = "unchanged"
+	LimitIncreased LimitChangeKind = "increased"
+	LimitDecreased LimitChangeKind = "decreased"
+	LimitEnabled   LimitChangeKind = "enabled"
+	LimitDisabled  LimitChangeKind = "disabled"
+)
+
+// LimitChange describes the change of a single limit
+type LimitChange struct {
+	// Tier is the JetStream tier of the limit, empty for the other limits
+	Tier string `json:"tier,omitempty"`
+	// Field is the JSON name of the limit, or "tier" for a tier being added or removed
+	Field string          `json:"field"`
+	Kind  LimitChangeKind `json:"kind"`
+	Old   interface{}     `json:"old"`
+	New   interface{}     `json:"new"`
+	// Reduction is true if the change restricts the account and may break running workloads
+	Reduction bool `json:"reduction,omitempty"`
+}
+
+// LimitsReport lists the changes of every limit in the order of the limit structs,
+// followed by the JetStream tiers sorted by name
+type LimitsReport struct {
+	Changes []*LimitChange `json:"changes"`
+}
+
+// Changed returns the limits that changed
+func (r *LimitsReport) Changed() []*LimitChange {
+	var c []*LimitChange
+	for _, v := range r.Changes {
+		if v.Kind != LimitUnchanged {
+			c = append(c, v)
+		}
+	}
+	return c
+}
+
+// Reductions returns the changes that restrict the account
+func (r *LimitsReport) Reductions() []*LimitChange {
+	var c []*LimitChange
+	for _, v := range r.Changes {
+		if v.Reduction {
+			c = append(c, v)
+		}
+	}
+	return c
+}
+
+// HasReductions returns true if any change restricts the account
+func (r *LimitsReport) HasReductions() bool {
+	return len(r.Reductions()) > 0
+}
+
+// limitKind describes how the server interprets the value of a numeric limit
+type limitKind int
+
+const (
+	// countLimit is unlimited with NoLimit, 0 allows nothing
+	countLimit limitKind = iota
+	// optionalLimit is unlimited with NoLimit or 0
+	optionalLimit
+	// storageLimit is unlimited with NoLimit, 0 disables the storage
+	storageLimit
+)
+
+func (r *LimitsReport) number(tier string, field string, kind limitKind, old int64, new int64) {
+	c := &LimitChange{Tier: tier, Field: field, Old: old, New: new, Kind: LimitUnchanged}
+	r.Changes = append(r.Changes, c)
+	if kind == optionalLimit {
+		if old == 0 {
+			old = NoLimit
+		}
+		if new == 0 {
+			new = NoLimit
+		}
+	}
+	switch {
+	case old == new:
+	case kind == storageLimit && old == 0:
+		c.Kind = LimitEnabled
+	case kind == storageLimit && new == 0:
+		c.Kind = LimitDisabled
+		c.Reduction = true
+	case old == NoLimit:
+		c.Kind = LimitDecreased
+		c.Reduction = true
+	case new == NoLimit || new > old:
+		c.Kind = LimitIncreased
+	default:
+		c.Kind = LimitDecreased
+		c.Reduction = true
+	}
+}
+
+// flag adds the change of a boolean limit, restrictive flags restrict the account when enabled
+func (r *LimitsReport) flag(tier string, field string, restrictive bool, old bool, new bool) {
+	c := &LimitChange{Tier: tier, Field: field, Old: old, New: new, Kind: LimitUnchanged}
+	r.Changes = append(r.Changes, c)
+	switch {
+	case old == new:
+	case new:
+		c.Kind = LimitEnabled
+		c.Reduction = restrictive
+	default:
+		c.Kind = LimitDisabled
+		c.Reduction = !restrictive
+	}
+}
+
+func (r *LimitsReport) jetStream(tier string, old *JetStreamLimits, new *JetStreamLimits) {
+	r.number(tier, "mem_storage", storageLimit, old.MemoryStorage, new.MemoryStorage)
+	r.number(tier, "disk_storage", storageLimit, old.DiskStorage, new.DiskStorage)
+	r.number(tier, "streams", optionalLimit, old.Streams, new.Streams)
+	r.number(tier, "consumer", optionalLimit, old.Consumer, new.Consumer)
+	r.number(tier, "max_ack_pending", optionalLimit, old.MaxAckPending, new.MaxAckPending)
+	r.number(tier, "mem_max_stream_bytes", optionalLimit, old.MemoryMaxStreamBytes, new.MemoryMaxStreamBytes)
+	r.number(tier, "disk_max_stream_bytes", optionalLimit, old.DiskMaxStreamBytes, new.DiskMaxStreamBytes)
+	r.flag(tier, "max_bytes_required", true, old.MaxBytesRequired, new.MaxBytesRequired)
+}
+
+// CompareLimits reports the change of every limit from old to new. JetStream tiers only present
+// on one side are reported as enabled or disabled and compared against empty limits.
+func CompareLimits(old *OperatorLimits, new *OperatorLimits) *LimitsReport {
+	if old == nil {
+		old = &OperatorLimits{}
+	}
+	if new == nil {
+		new = &OperatorLimits{}
+	}
+	r := &LimitsReport{}
+	r.number("", "subs", countLimit, old.Subs, new.Subs)
+	r.number("", "data", countLimit, old.Data, new.Data)
+	r.number("", "payload", countLimit, old.Payload, new.Payload)
+	r.number("", "imports", countLimit, old.Imports, new.Imports)
+	r.number("", "exports", countLimit, old.Exports, new.Exports)
+	r.flag("", "wildcards", false, old.WildcardExports, new.WildcardExports)
+	r.flag("", "disallow_bearer", true, old.DisallowBearer, new.DisallowBearer)
+	r.number("", "conn", countLimit, old.Conn, new.Conn)
+	r.number("", "leaf", countLimit, old.LeafNodeConn, new.LeafNodeConn)
+	r.jetStream("", &old.JetStreamLimits, &new.JetStreamLimits)
+
+	tiers := make(map[string]bool)
+	for k := range old.JetStreamTieredLimits {
+		tiers[k] = true
+	}
+	for k := range new.JetStreamTieredLimits {
+		tiers[k] = true
+	}
+	names := make([]string, 0, len(tiers))
+	for k := range tiers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, k := range names {
+		o, inOld := old.JetStreamTieredLimits[k]
+		n, inNew := new.JetStreamTieredLimits[k]
+		r.flag(k, "tier", false, inOld, inNew)
+		r.jetStream(k, &o, &n)
+	}
+	return r
+}
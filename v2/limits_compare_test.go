@@ -0,0 +1,111 @@
+/*
+ * Copyright 2026 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwt
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func findChange(r *LimitsReport, tier string, field string) *LimitChange {
+	for _, c := range r.Changes {
+		if c.Tier == tier && c.Field == field {
+			return c
+		}
+	}
+	return nil
+}
+
+func TestCompareLimits_Unchanged(t *testing.T) {
+	a := NewAccountClaims(publicKey(createAccountNKey(t), t))
+	r := CompareLimits(&a.Limits, &a.Limits)
+	AssertEquals(17, len(r.Changes), t)
+	AssertEquals(0, len(r.Changed()), t)
+	AssertFalse(r.HasReductions(), t)
+}
+
+func TestCompareLimits_Kinds(t *testing.T) {
+	a := NewAccountClaims(publicKey(createAccountNKey(t), t))
+	old := a.Limits
+	new := a.Limits
+	old.Subs = 10
+	new.Subs = NoLimit
+	new.Data = 1024
+	old.Conn = 5
+	new.Conn = 2
+	old.Imports = 0
+	new.Imports = 3
+	new.WildcardExports = false
+	new.DisallowBearer = true
+	new.MemoryStorage = 1024
+	old.DiskStorage = 1024
+	new.DiskStorage = 0
+	old.MaxAckPending = NoLimit
+	new.MaxAckPending = 0
+	new.Streams = 5
+
+	r := CompareLimits(&old, &new)
+	check := func(field string, kind LimitChangeKind, reduction bool) {
+		c := findChange(r, "", field)
+		AssertTrue(c != nil, t)
+		AssertEquals(kind, c.Kind, t)
+		AssertEquals(reduction, c.Reduction, t)
+	}
+	check("subs", LimitIncreased, false)
+	check("data", LimitDecreased, true)
+	check("conn", LimitDecreased, true)
+	check("imports", LimitIncreased, false)
+	check("wildcards", LimitDisabled, true)
+	check("disallow_bearer", LimitEnabled, true)
+	check("mem_storage", LimitEnabled, false)
+	check("disk_storage", LimitDisabled, true)
+	// NoLimit and 0 both don't limit max ack pending
+	check("max_ack_pending", LimitUnchanged, false)
+	check("streams", LimitDecreased, true)
+	check("payload", LimitUnchanged, false)
+	AssertEquals(int64(10), findChange(r, "", "subs").Old, t)
+	AssertEquals(6, len(r.Reductions()), t)
+}
+
+func TestCompareLimits_Tiers(t *testing.T) {
+	old := OperatorLimits{JetStreamTieredLimits: JetStreamTieredLimits{
+		"R1": {DiskStorage: 1024, Streams: 10},
+		"R3": {DiskStorage: 1024},
+	}}
+	new := OperatorLimits{JetStreamTieredLimits: JetStreamTieredLimits{
+		"R1": {DiskStorage: 2048, Streams: 5},
+		"R5": {DiskStorage: 1024},
+	}}
+	r := CompareLimits(&old, &new)
+	AssertEquals(LimitUnchanged, findChange(r, "R1", "tier").Kind, t)
+	AssertEquals(LimitIncreased, findChange(r, "R1", "disk_storage").Kind, t)
+	AssertEquals(LimitDecreased, findChange(r, "R1", "streams").Kind, t)
+	c := findChange(r, "R3", "tier")
+	AssertEquals(LimitDisabled, c.Kind, t)
+	AssertTrue(c.Reduction, t)
+	AssertEquals(LimitDisabled, findChange(r, "R3", "disk_storage").Kind, t)
+	c = findChange(r, "R5", "tier")
+	AssertEquals(LimitEnabled, c.Kind, t)
+	AssertFalse(c.Reduction, t)
+
+	// the report is machine readable
+	d, err := json.Marshal(r)
+	AssertNoError(err, t)
+	var decoded LimitsReport
+	AssertNoError(json.Unmarshal(d, &decoded), t)
+	AssertEquals(len(r.Changes), len(decoded.Changes), t)
+	AssertEquals("R5", decoded.Changes[len(decoded.Changes)-9].Tier, t)
+}
@@ -0,0 +1,253 @@
+/*
+ * Copyright 2026 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+)
+
+// MappingSelection is the outcome of selecting a destination for a published subject
+type MappingSelection struct {
+	// Source is the mapping source subject that matched the published subject
+	Source Subject
+	// Destination is the weighted mapping destination that was selected, empty if dropped
+	Destination Subject
+	// Subject is the subject the message is delivered to, empty if dropped
+	Subject string
+	// PassThrough is true if the selection fell into the share not covered by weights,
+	// the message is delivered to the published subject
+	PassThrough bool
+	// Dropped is true if the selection fell into the share not covered by weights
+	// and the source is listed as destination
+	Dropped bool
+}
+
+// MappingDistribution reports how often each destination was selected over a number of samples
+type MappingDistribution struct {
+	Source  Subject
+	Cluster string
+	Samples int
+	// Expected holds the weight of each destination in percent, including the source
+	// for the share not covered by weights if it is passed through
+	Expected map[Subject]uint8
+	// Counts holds the number of times each destination was selected
+	Counts map[Subject]int
+	// Dropped is the number of samples that were dropped
+	Dropped int
+}
+
+// Percent returns the observed share of samples selecting the destination
+func (d *MappingDistribution) Percent(dest Subject) float64 {
+	if d.Samples == 0 {
+		return 0
+	}
+	return float64(d.Counts[dest]) * 100 / float64(d.Samples)
+}
+
+// DroppedPercent returns the observed share of dropped samples
+func (d *MappingDistribution) DroppedPercent() float64 {
+	if d.Samples == 0 {
+		return 0
+	}
+	return float64(d.Dropped) * 100 / float64(d.Samples)
+}
+
+type simulatedDestination struct {
+	mapping WeightedMapping
+	tr      *SubjectTransform
+	// cumulative weight, as used by the server for selection
+	weight uint8
+	// passThrough is the destination the server adds for the share not covered by weights,
+	// it delivers to the published subject and has no transform
+	passThrough bool
+}
+
+// MappingSimulator selects mapping destinations the way the server does, including
+// cluster specific destinations overriding global ones. When weights add up to less than 100
+// the server delivers the share not covered to the source subject itself, unless the
+// source is listed as a destination, in which case messages of that share are dropped.
+type MappingSimulator struct {
+	sources []Subject
+	global  map[Subject][]*simulatedDestination
+	cluster map[Subject]map[string][]*simulatedDestination
+	rng     *rand.Rand
+}
+
+// cumulate adds the pass through destination if the weights don't add up to 100
+// and src is not listed as destination, then sorts and accumulates the weights
+func cumulate(src Subject, dests []*simulatedDestination, listed bool) []*simulatedDestination {
+	var total uint8
+	for _, d := range dests {
+		total += d.mapping.GetWeight()
+	}
+	if total != 100 && !listed {
+		dests = append(dests, &simulatedDestination{
+			mapping:     WeightedMapping{Subject: src, Weight: 100 - total},
+			passThrough: true,
+		})
+	}
+	sort.SliceStable(dests, func(i, j int) bool {
+		return dests[i].mapping.GetWeight() < dests[j].mapping.GetWeight()
+	})
+	var w uint8
+	for _, d := range dests {
+		w += d.mapping.GetWeight()
+		d.weight = w
+	}
+	return dests
+}
+
+// NewMappingSimulator creates a simulator for the mapping. The mapping needs to be valid.
+// rng is used to select destinations and should be seeded for reproducible results.
+func NewMappingSimulator(m Mapping, rng *rand.Rand) (*MappingSimulator, error) {
+	if rng == nil {
+		return nil, errors.New("a random number generator is required")
+	}
+	vr := CreateValidationResults()
+	m.Validate(vr)
+	if errs := vr.Errors(); len(errs) > 0 {
+		return nil, errs[0]
+	}
+	s := &MappingSimulator{
+		global:  make(map[Subject][]*simulatedDestination),
+		cluster: make(map[Subject]map[string][]*simulatedDestination),
+		rng:     rng,
+	}
+	for src, wm := range m {
+		s.sources = append(s.sources, src)
+		listed := false
+		for _, e := range wm {
+			if e.Subject == src {
+				listed = true
+			}
+			tr, err := NewSubjectTransform(src, e.Subject)
+			if err != nil {
+				return nil, err
+			}
+			d := &simulatedDestination{mapping: e, tr: tr}
+			if e.Cluster == "" {
+				s.global[src] = append(s.global[src], d)
+			} else {
+				if s.cluster[src] == nil {
+					s.cluster[src] = make(map[string][]*simulatedDestination)
+				}
+				s.cluster[src][e.Cluster] = append(s.cluster[src][e.Cluster], d)
+			}
+		}
+		s.global[src] = cumulate(src, s.global[src], listed)
+		for c, d := range s.cluster[src] {
+			s.cluster[src][c] = cumulate(src, d, listed)
+		}
+	}
+	sort.Slice(s.sources, func(i, j int) bool { return s.sources[i] < s.sources[j] })
+	return s, nil
+}
+
+// source returns the mapping source matching the subject. A source equal to the
+// subject takes precedence, otherwise the first matching source in sorted order is used.
+func (s *MappingSimulator) source(subject string) (Subject, bool) {
+	if _, ok := s.global[Subject(subject)]; ok {
+		return Subject(subject), true
+	}
+	if _, ok := s.cluster[Subject(subject)]; ok {
+		return Subject(subject), true
+	}
+	for _, src := range s.sources {
+		if Subject(subject).IsContainedIn(src) {
+			return src, true
+		}
+	}
+	return "", false
+}
+
+func (s *MappingSimulator) destinations(src Subject, cluster string) []*simulatedDestination {
+	if dests, ok := s.cluster[src][cluster]; ok && cluster != "" {
+		return dests
+	}
+	return s.global[src]
+}
+
+// Select picks the destination for a published literal subject in the named cluster.
+// An error is returned if no mapping applies to the subject.
+func (s *MappingSimulator) Select(subject string, cluster string) (*MappingSelection, error) {
+	src, ok := s.source(subject)
+	if !ok {
+		return nil, fmt.Errorf("no mapping matches subject %q", subject)
+	}
+	sel := &MappingSelection{Source: src}
+	dests := s.destinations(src, cluster)
+	var d *simulatedDestination
+	if len(dests) == 1 && dests[0].weight == 100 {
+		d = dests[0]
+	} else {
+		w := uint8(s.rng.Int31n(100))
+		for _, rd := range dests {
+			if w < rd.weight {
+				d = rd
+				break
+			}
+		}
+	}
+	if d == nil {
+		sel.Dropped = true
+		return sel, nil
+	}
+	sel.Destination = d.mapping.Subject
+	if d.passThrough {
+		sel.Subject = subject
+		sel.PassThrough = true
+		return sel, nil
+	}
+	to, err := d.tr.Transform(subject)
+	if err != nil {
+		return nil, err
+	}
+	sel.Subject = to
+	return sel, nil
+}
+
+// Distribution selects a destination for the subject samples times and reports the outcome
+func (s *MappingSimulator) Distribution(subject string, cluster string, samples int) (*MappingDistribution, error) {
+	src, ok := s.source(subject)
+	if !ok {
+		return nil, fmt.Errorf("no mapping matches subject %q", subject)
+	}
+	d := &MappingDistribution{
+		Source:   src,
+		Cluster:  cluster,
+		Expected: make(map[Subject]uint8),
+		Counts:   make(map[Subject]int),
+	}
+	for _, e := range s.destinations(src, cluster) {
+		d.Expected[e.mapping.Subject] += e.mapping.GetWeight()
+	}
+	for i := 0; i < samples; i++ {
+		sel, err := s.Select(subject, cluster)
+		if err != nil {
+			return nil, err
+		}
+		d.Samples++
+		if sel.Dropped {
+			d.Dropped++
+		} else {
+			d.Counts[sel.Destination]++
+		}
+	}
+	return d, nil
+}
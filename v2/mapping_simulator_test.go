@@ -0,0 +1,133 @@
+/*
+ * Copyright 2026 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwt
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestMappingSimulator_Select(t *testing.T) {
+	m := Mapping{}
+	m["svc.*"] = []WeightedMapping{{Subject: "svc.v1.$1"}}
+	s, err := NewMappingSimulator(m, rand.New(rand.NewSource(1)))
+	AssertNoError(err, t)
+
+	sel, err := s.Select("svc.req", "")
+	AssertNoError(err, t)
+	AssertFalse(sel.Dropped, t)
+	AssertEquals(Subject("svc.*"), sel.Source, t)
+	AssertEquals(Subject("svc.v1.$1"), sel.Destination, t)
+	AssertEquals("svc.v1.req", sel.Subject, t)
+
+	_, err = s.Select("other", "")
+	AssertTrue(err != nil, t)
+}
+
+func TestMappingSimulator_Distribution(t *testing.T) {
+	m := Mapping{}
+	m["svc"] = []WeightedMapping{
+		{Subject: "svc.v1", Weight: 70},
+		{Subject: "svc.v2", Weight: 20},
+	}
+	s, err := NewMappingSimulator(m, rand.New(rand.NewSource(42)))
+	AssertNoError(err, t)
+
+	d, err := s.Distribution("svc", "", 10000)
+	AssertNoError(err, t)
+	AssertEquals(10000, d.Samples, t)
+	AssertEquals(uint8(70), d.Expected["svc.v1"], t)
+	AssertEquals(uint8(20), d.Expected["svc.v2"], t)
+	// the share not covered by weights keeps the published subject
+	AssertEquals(uint8(10), d.Expected["svc"], t)
+	AssertEquals(0, d.Dropped, t)
+	AssertEquals(10000, d.Counts["svc.v1"]+d.Counts["svc.v2"]+d.Counts["svc"], t)
+	within := func(v, expected float64) bool {
+		return v > expected-3 && v < expected+3
+	}
+	AssertTrue(within(d.Percent("svc.v1"), 70), t)
+	AssertTrue(within(d.Percent("svc.v2"), 20), t)
+	AssertTrue(within(d.Percent("svc"), 10), t)
+
+	// the same seed produces the same distribution
+	s2, err := NewMappingSimulator(m, rand.New(rand.NewSource(42)))
+	AssertNoError(err, t)
+	d2, err := s2.Distribution("svc", "", 10000)
+	AssertNoError(err, t)
+	AssertEquals(d.Counts["svc"], d2.Counts["svc"], t)
+	AssertEquals(d.Counts["svc.v1"], d2.Counts["svc.v1"], t)
+}
+
+func TestMappingSimulator_PassThroughAndDrop(t *testing.T) {
+	m := Mapping{}
+	m["svc.*"] = []WeightedMapping{{Subject: "svc.v2.$1", Weight: 10}}
+	s, err := NewMappingSimulator(m, rand.New(rand.NewSource(3)))
+	AssertNoError(err, t)
+	passed := 0
+	for i := 0; i < 100; i++ {
+		sel, err := s.Select("svc.req", "")
+		AssertNoError(err, t)
+		AssertFalse(sel.Dropped, t)
+		if sel.PassThrough {
+			passed++
+			AssertEquals(Subject("svc.*"), sel.Destination, t)
+			AssertEquals("svc.req", sel.Subject, t)
+		} else {
+			AssertEquals("svc.v2.req", sel.Subject, t)
+		}
+	}
+	AssertTrue(passed > 0 && passed < 100, t)
+
+	// listing the source explicitly drops the share not covered by weights
+	m["svc.*"] = []WeightedMapping{{Subject: "svc.v2.$1", Weight: 10}, {Subject: "svc.*", Weight: 40}}
+	s, err = NewMappingSimulator(m, rand.New(rand.NewSource(3)))
+	AssertNoError(err, t)
+	d, err := s.Distribution("svc.req", "", 1000)
+	AssertNoError(err, t)
+	AssertEquals(1000, d.Counts["svc.v2.$1"]+d.Counts["svc.*"]+d.Dropped, t)
+	AssertTrue(d.Dropped > 0, t)
+	AssertEquals(uint8(40), d.Expected["svc.*"], t)
+}
+
+func TestMappingSimulator_Cluster(t *testing.T) {
+	m := Mapping{}
+	m["q"] = []WeightedMapping{
+		{Subject: "global"},
+		{Subject: "east", Cluster: "EAST", Weight: 50},
+	}
+	s, err := NewMappingSimulator(m, rand.New(rand.NewSource(7)))
+	AssertNoError(err, t)
+
+	d, err := s.Distribution("q", "WEST", 100)
+	AssertNoError(err, t)
+	AssertEquals(100, d.Counts["global"], t)
+
+	d, err = s.Distribution("q", "EAST", 1000)
+	AssertNoError(err, t)
+	AssertEquals(0, d.Counts["global"], t)
+	AssertEquals(0, d.Dropped, t)
+	AssertEquals(1000, d.Counts["east"]+d.Counts["q"], t)
+	AssertTrue(d.Counts["east"] > 0 && d.Counts["q"] > 0, t)
+}
+
+func TestMappingSimulator_InvalidMapping(t *testing.T) {
+	m := Mapping{}
+	m["q"] = []WeightedMapping{{Subject: "a", Weight: 90}, {Subject: "b", Weight: 90}}
+	_, err := NewMappingSimulator(m, rand.New(rand.NewSource(1)))
+	AssertTrue(err != nil, t)
+	_, err = NewMappingSimulator(Mapping{}, nil)
+	AssertTrue(err != nil, t)
+}
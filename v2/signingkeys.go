@@ -89,6 +89,9 @@ func (us UserScope) Validate(vr *ValidationResults) {
 	if !nkeys.IsValidPublicAccountKey(us.Key) {
 		vr.AddError("%s is not an account public key", us.Key)
 	}
+	if us.Template.Resp != nil {
+		us.Template.Resp.Validate(vr)
+	}
 }
 
 func (us UserScope) ValidateScopedSigner(c Claims) error {
@@ -0,0 +1,309 @@
+/*
+ * Copyright 2026 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwt
+
+import (
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// TransformFunction is the name of a mapping function usable in a destination token
+type TransformFunction string
+
+const (
+	// TransformWildcard {{wildcard(n)}} is replaced by the token matching the nth * in the source, same as $n
+	TransformWildcard TransformFunction = "wildcard"
+	// TransformPartition {{partition(p,n...)}} is replaced by a partition number in [0,p)
+	// computed by hashing the tokens matching the listed * in the source
+	TransformPartition TransformFunction = "partition"
+	// TransformSplit {{split(n,delim)}} splits the token matching the nth * on delim into multiple tokens
+	TransformSplit TransformFunction = "split"
+	// TransformSplitFromLeft {{splitFromLeft(n,pos)}} splits the token matching the nth * in two at pos from the left
+	TransformSplitFromLeft TransformFunction = "splitfromleft"
+	// TransformSplitFromRight {{splitFromRight(n,pos)}} splits the token matching the nth * in two at pos from the right
+	TransformSplitFromRight TransformFunction = "splitfromright"
+	// TransformSliceFromLeft {{sliceFromLeft(n,size)}} slices the token matching the nth * in tokens of size from the left
+	TransformSliceFromLeft TransformFunction = "slicefromleft"
+	// TransformSliceFromRight {{sliceFromRight(n,size)}} slices the token matching the nth * in tokens of size from the right
+	TransformSliceFromRight TransformFunction = "slicefromright"
+)
+
+var transformFunctionRegEx = regexp.MustCompile(`^\{\{\s*([a-zA-Z]+)\s*\((.*)\)\s*\}\}$`)
+
+type transformToken struct {
+	literal string
+	// unbound is set for a * in the destination without a matching * in the source
+	unbound  bool
+	function TransformFunction
+	// wildcard indexes are 1 based and refer to the * tokens in the source
+	wildcards []int
+	intArg    int
+	strArg    string
+}
+
+// SubjectTransform is a parsed subject mapping from a source subject to a destination
+// that can contain $n wildcard references and mapping functions such as {{partition(10,1)}}.
+type SubjectTransform struct {
+	Source      Subject
+	Destination Subject
+	// position of the nth * in the source subject
+	wildcards []int
+	fwc       bool
+	tokens    []transformToken
+}
+
+// NewSubjectTransform parses the destination and validates function names, arities
+// and wildcard indexes against the source subject.
+func NewSubjectTransform(source Subject, destination Subject) (*SubjectTransform, error) {
+	if source == "" || destination == "" {
+		return nil, fmt.Errorf("source and destination subject need to be set")
+	}
+	t := &SubjectTransform{Source: source, Destination: destination}
+	srcTokens := strings.Split(string(source), ".")
+	for i, tk := range srcTokens {
+		if tk == "*" {
+			t.wildcards = append(t.wildcards, i)
+		}
+	}
+	t.fwc = srcTokens[len(srcTokens)-1] == ">"
+	destTokens := strings.Split(string(destination), ".")
+	destFwc := destTokens[len(destTokens)-1] == ">"
+	if t.fwc != destFwc {
+		return nil, fmt.Errorf("both, source %q and destination %q, need to end or not end in >", source, destination)
+	}
+	pwcIdx := 0
+	for _, tk := range destTokens {
+		if tk == "*" {
+			// as in the server, * tokens in the destination refer to the * tokens of the source in order
+			pwcIdx++
+			if pwcIdx > len(t.wildcards) {
+				t.tokens = append(t.tokens, transformToken{literal: tk, unbound: true})
+			} else {
+				t.tokens = append(t.tokens, transformToken{function: TransformWildcard, wildcards: []int{pwcIdx}})
+			}
+			continue
+		}
+		tt, err := t.parseToken(tk)
+		if err != nil {
+			return nil, err
+		}
+		t.tokens = append(t.tokens, tt)
+	}
+	return t, nil
+}
+
+func (t *SubjectTransform) wildcardIndex(arg string) (int, error) {
+	idx, err := strconv.Atoi(arg)
+	if err != nil {
+		return 0, fmt.Errorf("wildcard index %q in %q is not a number", arg, t.Destination)
+	}
+	if idx < 1 || idx > len(t.wildcards) {
+		return 0, fmt.Errorf("wildcard index %d in %q is out of range, %q has %d wildcards",
+			idx, t.Destination, t.Source, len(t.wildcards))
+	}
+	return idx, nil
+}
+
+func (t *SubjectTransform) intArg(fn TransformFunction, arg string) (int, error) {
+	v, err := strconv.Atoi(arg)
+	if err != nil {
+		return 0, fmt.Errorf("argument %q of %s in %q is not a number", arg, fn, t.Destination)
+	}
+	if v < 1 {
+		return 0, fmt.Errorf("argument %d of %s in %q needs to be positive", v, fn, t.Destination)
+	}
+	return v, nil
+}
+
+func (t *SubjectTransform) parseToken(tk string) (transformToken, error) {
+	if len(tk) > 1 && tk[0] == '$' {
+		if _, err := strconv.Atoi(tk[1:]); err == nil {
+			idx, err := t.wildcardIndex(tk[1:])
+			if err != nil {
+				return transformToken{}, err
+			}
+			return transformToken{function: TransformWildcard, wildcards: []int{idx}}, nil
+		}
+	}
+	if !strings.HasPrefix(tk, "{{") {
+		return transformToken{literal: tk}, nil
+	}
+	m := transformFunctionRegEx.FindStringSubmatch(tk)
+	if m == nil {
+		return transformToken{}, fmt.Errorf("mapping function %q in %q is malformed", tk, t.Destination)
+	}
+	fn := TransformFunction(strings.ToLower(m[1]))
+	var args []string
+	if strings.TrimSpace(m[2]) != "" {
+		for _, a := range strings.Split(m[2], ",") {
+			args = append(args, strings.TrimSpace(a))
+		}
+	}
+	arity := func(n int) error {
+		if len(args) != n {
+			return fmt.Errorf("mapping function %s in %q requires %d arguments, got %d", fn, t.Destination, n, len(args))
+		}
+		return nil
+	}
+	tt := transformToken{function: fn}
+	switch fn {
+	case TransformWildcard:
+		if err := arity(1); err != nil {
+			return tt, err
+		}
+		idx, err := t.wildcardIndex(args[0])
+		if err != nil {
+			return tt, err
+		}
+		tt.wildcards = []int{idx}
+	case TransformPartition:
+		if len(args) < 2 {
+			return tt, fmt.Errorf("mapping function %s in %q requires the number of partitions and at least one wildcard index", fn, t.Destination)
+		}
+		v, err := t.intArg(fn, args[0])
+		if err != nil {
+			return tt, err
+		}
+		tt.intArg = v
+		for _, a := range args[1:] {
+			idx, err := t.wildcardIndex(a)
+			if err != nil {
+				return tt, err
+			}
+			tt.wildcards = append(tt.wildcards, idx)
+		}
+	case TransformSplit:
+		if err := arity(2); err != nil {
+			return tt, err
+		}
+		idx, err := t.wildcardIndex(args[0])
+		if err != nil {
+			return tt, err
+		}
+		if args[1] == "" {
+			return tt, fmt.Errorf("mapping function %s in %q requires a delimiter", fn, t.Destination)
+		}
+		tt.wildcards = []int{idx}
+		tt.strArg = args[1]
+	case TransformSplitFromLeft, TransformSplitFromRight, TransformSliceFromLeft, TransformSliceFromRight:
+		if err := arity(2); err != nil {
+			return tt, err
+		}
+		idx, err := t.wildcardIndex(args[0])
+		if err != nil {
+			return tt, err
+		}
+		v, err := t.intArg(fn, args[1])
+		if err != nil {
+			return tt, err
+		}
+		tt.wildcards = []int{idx}
+		tt.intArg = v
+	default:
+		return tt, fmt.Errorf("unknown mapping function %q in %q", m[1], t.Destination)
+	}
+	return tt, nil
+}
+
+// Transform computes the destination for a literal subject matching the source. It fails if the
+// destination has more * tokens than the source, as the result would not be a literal subject.
+func (t *SubjectTransform) Transform(subject string) (string, error) {
+	if Subject(subject).HasWildCards() {
+		return "", fmt.Errorf("subject %q is not a literal subject", subject)
+	}
+	if !Subject(subject).IsContainedIn(t.Source) {
+		return "", fmt.Errorf("subject %q does not match %q", subject, t.Source)
+	}
+	src := strings.Split(subject, ".")
+	wc := func(idx int) string {
+		return src[t.wildcards[idx-1]]
+	}
+	var out []string
+	for i, tt := range t.tokens {
+		switch tt.function {
+		case "":
+			if tt.unbound {
+				return "", fmt.Errorf("* in destination %q has no matching * in source %q", t.Destination, t.Source)
+			}
+			if t.fwc && i == len(t.tokens)-1 {
+				out = append(out, src[len(strings.Split(string(t.Source), "."))-1:]...)
+			} else {
+				out = append(out, tt.literal)
+			}
+		case TransformWildcard:
+			out = append(out, wc(tt.wildcards[0]))
+		case TransformPartition:
+			var key []byte
+			for _, idx := range tt.wildcards {
+				key = append(key, wc(idx)...)
+			}
+			h := fnv.New32a()
+			h.Write(key)
+			out = append(out, strconv.Itoa(int(h.Sum32()%uint32(tt.intArg))))
+		case TransformSplit:
+			for _, s := range strings.Split(wc(tt.wildcards[0]), tt.strArg) {
+				if s != "" {
+					out = append(out, s)
+				}
+			}
+		case TransformSplitFromLeft:
+			s := wc(tt.wildcards[0])
+			if tt.intArg < len(s) {
+				out = append(out, s[:tt.intArg], s[tt.intArg:])
+			} else {
+				out = append(out, s)
+			}
+		case TransformSplitFromRight:
+			s := wc(tt.wildcards[0])
+			if tt.intArg < len(s) {
+				out = append(out, s[:len(s)-tt.intArg], s[len(s)-tt.intArg:])
+			} else {
+				out = append(out, s)
+			}
+		case TransformSliceFromLeft:
+			s := wc(tt.wildcards[0])
+			for len(s) > tt.intArg {
+				out = append(out, s[:tt.intArg])
+				s = s[tt.intArg:]
+			}
+			out = append(out, s)
+		case TransformSliceFromRight:
+			s := wc(tt.wildcards[0])
+			if r := len(s) % tt.intArg; r > 0 && len(s) > tt.intArg {
+				out = append(out, s[:r])
+				s = s[r:]
+			}
+			for len(s) > tt.intArg {
+				out = append(out, s[:tt.intArg])
+				s = s[tt.intArg:]
+			}
+			out = append(out, s)
+		}
+	}
+	return strings.Join(out, "."), nil
+}
+
+// Transform computes the destination of this weighted mapping for a literal subject matching from
+func (m *WeightedMapping) Transform(from Subject, subject string) (string, error) {
+	t, err := NewSubjectTransform(from, m.Subject)
+	if err != nil {
+		return "", err
+	}
+	return t.Transform(subject)
+}
@@ -0,0 +1,130 @@
+/*
+ * Copyright 2026 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwt
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSubjectTransform_Transform(t *testing.T) {
+	cases := []struct {
+		src, dest, subject, expected string
+	}{
+		{"foo", "bar", "foo", "bar"},
+		{"foo.*.*", "bar.$2.$1", "foo.a.b", "bar.b.a"},
+		{"foo.*.*", "bar.{{wildcard(2)}}.{{Wildcard(1)}}", "foo.a.b", "bar.b.a"},
+		{"foo.>", "bar.>", "foo.a.b", "bar.a.b"},
+		{"foo.*.>", "bar.$1.>", "foo.a.b.c", "bar.a.b.c"},
+		{"foo.*", "bar.{{split(1,-)}}", "foo.-a--b-", "bar.a.b"},
+		{"foo.*", "bar.{{splitFromLeft(1,2)}}", "foo.abcde", "bar.ab.cde"},
+		{"foo.*", "bar.{{SplitFromRight(1,2)}}", "foo.abcde", "bar.abc.de"},
+		{"foo.*", "bar.{{splitFromLeft(1,5)}}", "foo.abcde", "bar.abcde"},
+		{"foo.*", "bar.{{sliceFromLeft(1,2)}}", "foo.abcde", "bar.ab.cd.e"},
+		{"foo.*", "bar.{{SliceFromRight(1,2)}}", "foo.abcde", "bar.a.bc.de"},
+		{"foo.*", "bar.{{sliceFromRight(1,2)}}", "foo.abcd", "bar.ab.cd"},
+		{"foo.*", "bar.{{sliceFromLeft(1,9)}}", "foo.abcd", "bar.abcd"},
+		// * tokens in the destination refer to the * tokens of the source by position
+		{"foo.*", "bar.*", "foo.a", "bar.a"},
+		{"foo.*.*", "bar.*.x.*", "foo.a.b", "bar.a.x.b"},
+		{"foo.*.*.>", "bar.$2.*.>", "foo.a.b.c", "bar.b.a.c"},
+	}
+	for _, c := range cases {
+		tr, err := NewSubjectTransform(Subject(c.src), Subject(c.dest))
+		AssertNoError(err, t)
+		v, err := tr.Transform(c.subject)
+		AssertNoError(err, t)
+		AssertEquals(c.expected, v, t)
+	}
+}
+
+func TestSubjectTransform_Partition(t *testing.T) {
+	tr, err := NewSubjectTransform("foo.*.*", "part.{{partition(10,1,2)}}.$1.$2")
+	AssertNoError(err, t)
+	// the partition is the fnv-1a hash of the concatenated tokens ("ab") modulo 10
+	v, err := tr.Transform("foo.a.b")
+	AssertNoError(err, t)
+	AssertEquals("part.6.a.b", v, t)
+	for _, s := range []string{"foo.x.y", "foo.1.2", "foo.aaa.bbb"} {
+		v, err = tr.Transform(s)
+		AssertNoError(err, t)
+		p := strings.Split(v, ".")[1]
+		AssertTrue(len(p) == 1 && p[0] >= '0' && p[0] <= '9', t)
+	}
+}
+
+func TestSubjectTransform_ParseErrors(t *testing.T) {
+	cases := []struct {
+		src, dest string
+	}{
+		{"foo.*", "bar.$2"},
+		{"foo.*", "bar.{{wildcard(0)}}"},
+		{"foo.*", "bar.{{wildcard(1,2)}}"},
+		{"foo.*", "bar.{{unknown(1)}}"},
+		{"foo.*", "bar.{{wildcard(1)"},
+		{"foo.*", "bar.{{partition(10)}}"},
+		{"foo.*", "bar.{{partition(0,1)}}"},
+		{"foo.*", "bar.{{partition(a,1)}}"},
+		{"foo.*", "bar.{{split(1)}}"},
+		{"foo.*", "bar.{{splitFromLeft(1,-1)}}"},
+		{"foo.*", "bar.{{sliceFromRight(2,1)}}"},
+		{"foo.>", "bar"},
+		{"foo", "bar.>"},
+	}
+	for _, c := range cases {
+		_, err := NewSubjectTransform(Subject(c.src), Subject(c.dest))
+		if err == nil {
+			t.Fatalf("expected %q -> %q to fail", c.src, c.dest)
+		}
+	}
+}
+
+func TestSubjectTransform_NoMatch(t *testing.T) {
+	tr, err := NewSubjectTransform("foo.*", "bar.$1")
+	AssertNoError(err, t)
+	_, err = tr.Transform("baz.a")
+	AssertTrue(err != nil, t)
+	_, err = tr.Transform("foo.*")
+	AssertTrue(err != nil, t)
+}
+
+func TestSubjectTransform_UnboundWildcard(t *testing.T) {
+	// more * in the destination than in the source can't produce a literal subject
+	for _, c := range [][2]Subject{{"foo5.>", "to.*.>"}, {"foo.*", "bar.*.*"}} {
+		tr, err := NewSubjectTransform(c[0], c[1])
+		AssertNoError(err, t)
+		_, err = tr.Transform(strings.Replace(strings.Replace(string(c[0]), "*", "a", -1), ">", "b", -1))
+		AssertTrue(err != nil && strings.Contains(err.Error(), "has no matching * in source"), t)
+	}
+}
+
+func TestMapping_ValidateFunctions(t *testing.T) {
+	m := Mapping{}
+	m["foo.*"] = []WeightedMapping{{Subject: "bar.{{partition(3,1)}}.$1"}}
+	vr := CreateValidationResults()
+	m.Validate(vr)
+	AssertTrue(vr.IsEmpty(), t)
+
+	m["foo.*"] = []WeightedMapping{{Subject: "bar.{{partition(3,2)}}"}}
+	vr = CreateValidationResults()
+	m.Validate(vr)
+	AssertTrue(vr.IsBlocking(false), t)
+
+	wm := WeightedMapping{Subject: "bar.{{splitFromLeft(1,1)}}"}
+	v, err := wm.Transform("foo.*", "foo.ab")
+	AssertNoError(err, t)
+	AssertEquals("bar.a.b", v, t)
+}
@@ -0,0 +1,208 @@
+/*
+ * Copyright 2026 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwt
+
+import (
+	"strings"
+)
+
+const (
+	pwc = "*"
+	fwc = ">"
+)
+
+type sublistNode struct {
+	children map[string]*sublistNode
+	values   []interface{}
+}
+
+func newSublistNode() *sublistNode {
+	return &sublistNode{children: make(map[string]*sublistNode)}
+}
+
+// Sublist is a subject trie that stores values by subject and answers
+// queries using NATS wildcard semantics. Tokens are split on `.`, a `*`
+// token matches exactly one token and a trailing `>` matches one or more tokens.
+// A Sublist is not safe for concurrent modification.
+type Sublist struct {
+	root  *sublistNode
+	count int
+}
+
+// NewSublist creates an empty Sublist
+func NewSublist() *Sublist {
+	return &Sublist{root: newSublistNode()}
+}
+
+// Count returns the number of values stored in the sublist
+func (s *Sublist) Count() int {
+	return s.count
+}
+
+// Insert stores a value under the provided subject. The same subject can be
+// inserted multiple times, all values are retained.
+func (s *Sublist) Insert(subject Subject, v interface{}) {
+	n := s.root
+	for _, tk := range strings.Split(string(subject), ".") {
+		c, ok := n.children[tk]
+		if !ok {
+			c = newSublistNode()
+			n.children[tk] = c
+		}
+		n = c
+	}
+	n.values = append(n.values, v)
+	s.count++
+}
+
+// Match returns the values of all entries that contain the provided subject,
+// that is all entries e for which subject.IsContainedIn(e) is true.
+func (s *Sublist) Match(subject Subject) []interface{} {
+	var r []interface{}
+	matchLevel(s.root, strings.Split(string(subject), "."), func(n *sublistNode) {
+		r = append(r, n.values...)
+	})
+	return r
+}
+
+// Contains returns true if any entry contains the provided subject
+func (s *Sublist) Contains(subject Subject) bool {
+	found := false
+	matchLevel(s.root, strings.Split(string(subject), "."), func(n *sublistNode) {
+		if len(n.values) > 0 {
+			found = true
+		}
+	})
+	return found
+}
+
+// Within returns the values of all entries that are contained in the provided subject,
+// that is all entries e for which e.IsContainedIn(subject) is true.
+func (s *Sublist) Within(subject Subject) []interface{} {
+	var r []interface{}
+	withinLevel(s.root, strings.Split(string(subject), "."), func(n *sublistNode) {
+		r = append(r, n.values...)
+	})
+	return r
+}
+
+// Overlaps returns the values of all entries that share at least one
+// literal subject with the provided subject.
+func (s *Sublist) Overlaps(subject Subject) []interface{} {
+	var r []interface{}
+	overlapLevel(s.root, strings.Split(string(subject), "."), func(n *sublistNode) {
+		r = append(r, n.values...)
+	})
+	return r
+}
+
+func matchLevel(n *sublistNode, tokens []string, fn func(n *sublistNode)) {
+	if len(tokens) == 0 {
+		fn(n)
+		return
+	}
+	tk := tokens[0]
+	// a literal entry token only matches the same token in the subject
+	if tk != pwc && tk != fwc {
+		if c, ok := n.children[tk]; ok {
+			matchLevel(c, tokens[1:], fn)
+		}
+	}
+	if c, ok := n.children[pwc]; ok {
+		matchLevel(c, tokens[1:], fn)
+	}
+	if c, ok := n.children[fwc]; ok {
+		// a trailing > in the entry matches all remaining tokens
+		fn(c)
+		// a > that is not the last token of an entry only matches a literal >
+		if tk == fwc && len(tokens) > 1 {
+			matchLevel(c, tokens[1:], fn)
+		}
+	}
+}
+
+func withinLevel(n *sublistNode, tokens []string, fn func(n *sublistNode)) {
+	if len(tokens) == 0 {
+		fn(n)
+		return
+	}
+	tk := tokens[0]
+	switch tk {
+	case fwc:
+		if len(tokens) == 1 {
+			// everything below this level with at least one more token
+			for _, c := range n.children {
+				collectAll(c, fn)
+			}
+			return
+		}
+		if c, ok := n.children[fwc]; ok {
+			withinLevel(c, tokens[1:], fn)
+		}
+	case pwc:
+		for _, c := range n.children {
+			withinLevel(c, tokens[1:], fn)
+		}
+	default:
+		if c, ok := n.children[tk]; ok {
+			withinLevel(c, tokens[1:], fn)
+		}
+	}
+}
+
+func overlapLevel(n *sublistNode, tokens []string, fn func(n *sublistNode)) {
+	if len(tokens) == 0 {
+		fn(n)
+		return
+	}
+	tk := tokens[0]
+	switch tk {
+	case fwc:
+		for _, c := range n.children {
+			collectAll(c, fn)
+		}
+		return
+	case pwc:
+		// only a * in the query can overlap every token of the level
+		for ctk, c := range n.children {
+			if ctk == fwc {
+				// a trailing > in the entry overlaps all remaining tokens
+				fn(c)
+				if len(tokens) == 1 {
+					continue
+				}
+			}
+			overlapLevel(c, tokens[1:], fn)
+		}
+		return
+	}
+	if c, ok := n.children[tk]; ok {
+		overlapLevel(c, tokens[1:], fn)
+	}
+	if c, ok := n.children[pwc]; ok {
+		overlapLevel(c, tokens[1:], fn)
+	}
+	if c, ok := n.children[fwc]; ok {
+		fn(c)
+	}
+}
+
+func collectAll(n *sublistNode, fn func(n *sublistNode)) {
+	fn(n)
+	for _, c := range n.children {
+		collectAll(c, fn)
+	}
+}
@@ -0,0 +1,216 @@
+/*
+ * Copyright 2026 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwt
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+)
+
+var sublistSubjects = []Subject{
+	"foo", "foo.bar", "foo.*", "foo.>", "*.bar", "*", ">", "foo.bar.baz",
+	"foo.*.baz", "bar.>", "a.b.c", "a.*.c", "a.b.*", "*.*.*",
+}
+
+func sublistValues(v []interface{}) []string {
+	var r []string
+	for _, e := range v {
+		r = append(r, string(e.(Subject)))
+	}
+	sort.Strings(r)
+	return r
+}
+
+func newTestSublist() *Sublist {
+	sl := NewSublist()
+	for _, s := range sublistSubjects {
+		sl.Insert(s, s)
+	}
+	return sl
+}
+
+func TestSublist_MatchAgreesWithIsContainedIn(t *testing.T) {
+	sl := newTestSublist()
+	AssertEquals(len(sublistSubjects), sl.Count(), t)
+	for _, q := range sublistSubjects {
+		var expected []string
+		for _, e := range sublistSubjects {
+			if q.IsContainedIn(e) {
+				expected = append(expected, string(e))
+			}
+		}
+		sort.Strings(expected)
+		AssertEquals(fmt.Sprint(expected), fmt.Sprint(sublistValues(sl.Match(q))), t)
+		AssertEquals(len(expected) > 0, sl.Contains(q), t)
+	}
+}
+
+func TestSublist_WithinAgreesWithIsContainedIn(t *testing.T) {
+	sl := newTestSublist()
+	for _, q := range sublistSubjects {
+		var expected []string
+		for _, e := range sublistSubjects {
+			if e.IsContainedIn(q) {
+				expected = append(expected, string(e))
+			}
+		}
+		sort.Strings(expected)
+		AssertEquals(fmt.Sprint(expected), fmt.Sprint(sublistValues(sl.Within(q))), t)
+	}
+}
+
+func TestSublist_Overlaps(t *testing.T) {
+	sl := NewSublist()
+	for _, s := range []Subject{"a.*.c", "a.b", "b.>", "x.y.z"} {
+		sl.Insert(s, s)
+	}
+	AssertEquals("[a.*.c]", fmt.Sprint(sublistValues(sl.Overlaps("a.b.*"))), t)
+	AssertEquals("[a.*.c a.b]", fmt.Sprint(sublistValues(sl.Overlaps("a.>"))), t)
+	AssertEquals("[b.>]", fmt.Sprint(sublistValues(sl.Overlaps("*.q"))), t)
+	AssertEquals("[]", fmt.Sprint(sublistValues(sl.Overlaps("b"))), t)
+	AssertEquals("[a.*.c a.b b.> x.y.z]", fmt.Sprint(sublistValues(sl.Overlaps(">"))), t)
+	AssertFalse(sl.Contains("a.b.*"), t)
+	AssertTrue(sl.Contains("a.x.c"), t)
+}
+
+func TestSublist_OverlapsAgreesWithIntersects(t *testing.T) {
+	sl := newTestSublist()
+	for _, q := range sublistSubjects {
+		var expected []string
+		for _, e := range sublistSubjects {
+			if q.Intersects(e) {
+				expected = append(expected, string(e))
+			}
+		}
+		sort.Strings(expected)
+		AssertEquals(fmt.Sprint(expected), fmt.Sprint(sublistValues(sl.Overlaps(q))), t)
+	}
+}
+
+func TestSublist_DuplicateSubjects(t *testing.T) {
+	sl := NewSublist()
+	sl.Insert("foo", 1)
+	sl.Insert("foo", 2)
+	AssertEquals(2, sl.Count(), t)
+	AssertEquals(2, len(sl.Match("foo")), t)
+}
+
+func TestExports_Sublist(t *testing.T) {
+	exports := Exports{
+		&Export{Subject: "foo.*", Type: Stream},
+		&Export{Subject: "bar.>", Type: Service},
+	}
+	sl := exports.Sublist()
+	m := sl.Match("bar.baz.x")
+	AssertEquals(1, len(m), t)
+	AssertEquals(exports[1], m[0].(*Export), t)
+	for _, s := range []Subject{"foo.bar", "bar.x", "baz", "foo.bar.baz"} {
+		AssertEquals(exports.HasExportContainingSubject(s), sl.Contains(s), t)
+	}
+}
+
+func TestExports_Index(t *testing.T) {
+	exports := Exports{
+		&Export{Subject: "foo.>", Type: Stream},
+		nil,
+		&Export{Subject: "foo.*", Type: Service},
+		&Export{Subject: "foo.bar", Type: Service},
+	}
+	x := newExportIndex(exports)
+	AssertEquals(exports[2], x.find("foo.bar", Service), t)
+	AssertEquals(exports[0], x.find("foo.bar", Unknown), t)
+	AssertTrue(x.find("bar", Unknown) == nil, t)
+	i := &Import{Subject: "local", To: "foo.baz", Type: Service}
+	AssertEquals(exports[2], x.findImport(i), t)
+}
+
+func BenchmarkSublist_Match10k(b *testing.B) {
+	sl := NewSublist()
+	for i := 0; i < 10000; i++ {
+		sl.Insert(Subject(fmt.Sprintf("svc.%d.*", i)), i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sl.Match(Subject(fmt.Sprintf("svc.%d.req", i%10000)))
+	}
+}
+
+func BenchmarkExports_HasExportContainingSubject10k(b *testing.B) {
+	var exports Exports
+	for i := 0; i < 10000; i++ {
+		exports.Add(&Export{Subject: Subject(fmt.Sprintf("svc.%d.*", i)), Type: Service})
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		exports.HasExportContainingSubject(Subject(fmt.Sprintf("svc.%d.req", i%10000)))
+	}
+}
+
+func BenchmarkExports_Validate10k(b *testing.B) {
+	var exports Exports
+	for i := 0; i < 10000; i++ {
+		exports.Add(&Export{Subject: Subject(fmt.Sprintf("svc.%d.*", i)), Type: Service})
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		vr := CreateValidationResults()
+		exports.Validate(vr)
+		if !vr.IsEmpty() {
+			b.Fatal("expected no issues")
+		}
+	}
+}
+
+func BenchmarkExports_ValidatePartialOverlap10k(b *testing.B) {
+	var exports Exports
+	for i := 0; i < 5000; i++ {
+		exports.Add(&Export{Subject: Subject(fmt.Sprintf("svc.%d.a.*", i)), Type: Service})
+		exports.Add(&Export{Subject: Subject(fmt.Sprintf("svc.%d.*.b", i)), Type: Service})
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		vr := CreateValidationResults()
+		exports.Validate(vr)
+		if len(vr.Issues) != 5000 {
+			b.Fatalf("expected 5000 overlaps, got %d", len(vr.Issues))
+		}
+	}
+}
+
+func BenchmarkImports_Validate10k(b *testing.B) {
+	var imports Imports
+	for i := 0; i < 10000; i++ {
+		imports.Add(&Import{Subject: Subject(fmt.Sprintf("svc.%d.*", i)), Account: "A", Type: Service})
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		imports.Validate("", CreateValidationResults())
+	}
+}
+
+func BenchmarkPermissionMatcher_Allows10k(b *testing.B) {
+	var p Permission
+	for i := 0; i < 10000; i++ {
+		p.Allow.Add(fmt.Sprintf("app.%d.>", i))
+	}
+	p.Deny.Add("app.1.secret")
+	m := NewPermissionMatcher(&p)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.Allows(Subject(fmt.Sprintf("app.%d.x", i%10000)))
+	}
+}
@@ -0,0 +1,147 @@
+/*
+ * Copyright 2026 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwt
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	// SystemAccountServices is the subject of the per account system services, the account is the 4th token
+	SystemAccountServices = "$SYS.REQ.ACCOUNT.*.*"
+	// SystemAccountEvents is the subject of the per account system events, the account is the 3rd token
+	SystemAccountEvents = "$SYS.ACCOUNT.*.>"
+	// SystemServerPing is the subject used to ping all servers
+	SystemServerPing = "$SYS.REQ.SERVER.PING"
+)
+
+// SystemExports returns the standard exports of the system account. Per account services and
+// events use an account token position, so that every account can only import its own subjects.
+func SystemExports() Exports {
+	return Exports{
+		{
+			Name:                 "account-monitoring-services",
+			Subject:              SystemAccountServices,
+			Type:                 Service,
+			ResponseType:         ResponseTypeStream,
+			AccountTokenPosition: 4,
+			Info: Info{
+				Description: "Request account specific monitoring services for: SUBSZ, CONNZ, LEAFZ, JSZ and INFO",
+				InfoURL:     "https://docs.nats.io/nats-server/configuration/sys_accounts",
+			},
+		},
+		{
+			Name:                 "account-monitoring-streams",
+			Subject:              SystemAccountEvents,
+			Type:                 Stream,
+			AccountTokenPosition: 3,
+			Info: Info{
+				Description: "Account specific monitoring stream",
+				InfoURL:     "https://docs.nats.io/nats-server/configuration/sys_accounts",
+			},
+		},
+		{
+			Name:         "server-ping",
+			Subject:      SystemServerPing,
+			Type:         Service,
+			ResponseType: ResponseTypeStream,
+			Info: Info{
+				Description: "Ping all servers for their statistics",
+				InfoURL:     "https://docs.nats.io/nats-server/configuration/sys_accounts",
+			},
+		},
+	}
+}
+
+func systemAccount(operator *OperatorClaims) (string, error) {
+	if operator == nil {
+		return "", errors.New("operator is required")
+	}
+	if operator.SystemAccount == "" {
+		return "", fmt.Errorf("operator %q has no system account", operator.Subject)
+	}
+	return operator.SystemAccount, nil
+}
+
+// SystemImports returns the imports of the standard system exports for the account. Subjects with
+// an account token position are narrowed to the account.
+func SystemImports(operator *OperatorClaims, account string) (Imports, error) {
+	sys, err := systemAccount(operator)
+	if err != nil {
+		return nil, err
+	}
+	if account == sys {
+		return nil, errors.New("the system account can't import its own exports")
+	}
+	var imports Imports
+	for _, e := range SystemExports() {
+		subject, err := e.SubjectFor(account)
+		if err != nil {
+			return nil, err
+		}
+		imports.Add(&Import{Name: e.Name, Subject: subject, Account: sys, Type: e.Type})
+	}
+	return imports, nil
+}
+
+// AddSystemExports adds the standard system exports missing in the system account of the operator
+func AddSystemExports(operator *OperatorClaims, system *AccountClaims) error {
+	sys, err := systemAccount(operator)
+	if err != nil {
+		return err
+	}
+	if system == nil || system.Subject != sys {
+		return fmt.Errorf("system account %q is required", sys)
+	}
+	for _, e := range SystemExports() {
+		found := false
+		for _, x := range system.Exports {
+			if x != nil && x.Subject == e.Subject && x.Type == e.Type {
+				found = true
+				break
+			}
+		}
+		if !found {
+			system.Exports.Add(e)
+		}
+	}
+	return nil
+}
+
+// AddSystemImports adds the imports of the standard system exports missing in the account
+func AddSystemImports(operator *OperatorClaims, account *AccountClaims) error {
+	if account == nil {
+		return errors.New("account is required")
+	}
+	imports, err := SystemImports(operator, account.Subject)
+	if err != nil {
+		return err
+	}
+	for _, i := range imports {
+		found := false
+		for _, x := range account.Imports {
+			if x != nil && x.Account == i.Account && x.Subject == i.Subject && x.Type == i.Type {
+				found = true
+				break
+			}
+		}
+		if !found {
+			account.Imports.Add(i)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+/*
+ * Copyright 2026 The NATS Authors
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jwt
+
+import (
+	"testing"
+)
+
+func TestSystemImports(t *testing.T) {
+	operator := NewOperatorClaims(publicKey(createOperatorNKey(t), t))
+	system := NewAccountClaims(publicKey(createAccountNKey(t), t))
+	a := NewAccountClaims(publicKey(createAccountNKey(t), t))
+	b := NewAccountClaims(publicKey(createAccountNKey(t), t))
+
+	// the operator needs a system account
+	AssertTrue(AddSystemExports(operator, system) != nil, t)
+	AssertTrue(AddSystemImports(operator, a) != nil, t)
+	operator.SystemAccount = system.Subject
+
+	AssertTrue(AddSystemExports(operator, a) != nil, t)
+	AssertNoError(AddSystemExports(operator, system), t)
+	AssertNoError(AddSystemExports(operator, system), t)
+	AssertEquals(3, len(system.Exports), t)
+	vr := CreateValidationResults()
+	system.Validate(vr)
+	AssertTrue(vr.IsEmpty(), t)
+
+	AssertTrue(AddSystemImports(operator, system) != nil, t)
+	AssertNoError(AddSystemImports(operator, a), t)
+	AssertNoError(AddSystemImports(operator, a), t)
+	AssertNoError(AddSystemImports(operator, b), t)
+	AssertEquals(3, len(a.Imports), t)
+	AssertEquals(Subject("$SYS.REQ.ACCOUNT."+a.Subject+".*"), a.Imports[0].Subject, t)
+	AssertEquals(Subject("$SYS.ACCOUNT."+a.Subject+".>"), a.Imports[1].Subject, t)
+	AssertEquals(Subject(SystemServerPing), a.Imports[2].Subject, t)
+	for _, i := range a.Imports {
+		AssertEquals(system.Subject, i.Account, t)
+	}
+	vr = CreateValidationResults()
+	a.Validate(vr)
+	AssertTrue(vr.IsEmpty(), t)
+
+	report := NewAccountGraph(system, a, b).Validate()
+	AssertTrue(report[a.Subject].IsEmpty(), t)
+	AssertTrue(report[b.Subject].IsEmpty(), t)
+
+	// an account can't import the subjects of another account
+	b.Imports[0].Subject = a.Imports[0].Subject
+	report = NewAccountGraph(system, a, b).Validate()
+	AssertTrue(hasIssue(report[b.Subject], true, "needs to contain the importing account"), t)
+}
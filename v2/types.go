@@ -169,6 +169,124 @@ func (s RenamingSubject) ToSubject() Subject {
 	return Subject(bldr.String())
 }
 
+// references returns for every token of the renaming subject the index of the
+// wildcard in the import subject it refers to, or 0 if the token is not a reference.
+// * tokens are numbered in order of appearance, the same way the server does it.
+func (s RenamingSubject) references() []int {
+	tokens := strings.Split(string(s), ".")
+	refs := make([]int, len(tokens))
+	pwcIdx := 1
+	for i, tk := range tokens {
+		if tk == "*" {
+			refs[i] = pwcIdx
+			pwcIdx++
+		} else if len(tk) > 1 && tk[0] == '$' {
+			if idx, err := strconv.Atoi(tk[1:]); err == nil && idx > 0 {
+				refs[i] = idx
+			}
+		}
+	}
+	return refs
+}
+
+// wildcardValues returns the tokens of the literal subject that match the * tokens
+// of the subject it is contained in, and the tokens matched by a trailing >.
+func wildcardValues(s Subject, concrete string) ([]string, []string, error) {
+	if Subject(concrete).HasWildCards() {
+		return nil, nil, fmt.Errorf("subject %q is not a literal subject", concrete)
+	}
+	if !Subject(concrete).IsContainedIn(s) {
+		return nil, nil, fmt.Errorf("subject %q does not match %q", concrete, s)
+	}
+	tokens := strings.Split(concrete, ".")
+	var values []string
+	for i, tk := range strings.Split(string(s), ".") {
+		switch tk {
+		case "*":
+			values = append(values, tokens[i])
+		case ">":
+			return values, tokens[i:], nil
+		}
+	}
+	return values, nil, nil
+}
+
+// Transform maps a literal subject matching the import subject from to the local subject.
+// A reference $<n> is replaced by the token matching the nth * in from, * tokens are
+// numbered in order of appearance and a trailing > is replaced by the tokens matching the > in from.
+// If the renaming subject is empty, the subject is returned unchanged.
+func (s RenamingSubject) Transform(from Subject, concrete string) (string, error) {
+	values, tail, err := wildcardValues(from, concrete)
+	if err != nil {
+		return "", err
+	}
+	if s == "" {
+		return concrete, nil
+	}
+	tokens := strings.Split(string(s), ".")
+	for i, ref := range s.references() {
+		if ref > 0 {
+			if ref > len(values) {
+				return "", fmt.Errorf("reference %q in %q refers to a * that does not exist in %q", tokens[i], s, from)
+			}
+			tokens[i] = values[ref-1]
+		} else if tokens[i] == ">" && i == len(tokens)-1 {
+			if tail == nil {
+				return "", fmt.Errorf("%q ends in > but %q does not", s, from)
+			}
+			tokens = append(tokens[:i], tail...)
+		}
+	}
+	return strings.Join(tokens, "."), nil
+}
+
+// ReverseTransform is the inverse of Transform, it maps a literal local subject back to
+// the subject matching the import subject from. All * in from need to be referenced.
+func (s RenamingSubject) ReverseTransform(from Subject, concrete string) (string, error) {
+	if s == "" {
+		_, _, err := wildcardValues(from, concrete)
+		if err != nil {
+			return "", err
+		}
+		return concrete, nil
+	}
+	local, tail, err := wildcardValues(s.ToSubject(), concrete)
+	if err != nil {
+		return "", err
+	}
+	values := map[int]string{}
+	localIdx := 0
+	for _, ref := range s.references() {
+		if ref == 0 {
+			continue
+		}
+		v := local[localIdx]
+		localIdx++
+		if prev, ok := values[ref]; ok && prev != v {
+			return "", fmt.Errorf("subject %q contains different values for the same reference $%d", concrete, ref)
+		}
+		values[ref] = v
+	}
+	tokens := strings.Split(string(from), ".")
+	pwcIdx := 1
+	for i, tk := range tokens {
+		if tk == "*" {
+			v, ok := values[pwcIdx]
+			if !ok {
+				return "", fmt.Errorf("* number %d in %q is not referenced in %q", pwcIdx, from, s)
+			}
+			tokens[i] = v
+			pwcIdx++
+		} else if tk == ">" && i == len(tokens)-1 {
+			if tail == nil {
+				return "", fmt.Errorf("%q ends in > but %q does not", from, s)
+			}
+			tokens = append(tokens[:i], tail...)
+		}
+	}
+	return strings.Join(tokens, "."), nil
+}
+
 // Subject is a string that represents a NATS subject
 type Subject string
 
@@ -244,6 +362,51 @@ func (s Subject) IsContainedIn(other Subject) bool {
 	return true
 }
 
+// Intersects returns true if there is at least one literal subject matched by both subjects
+func (s Subject) Intersects(other Subject) bool {
+	return s.Intersection(other) != ""
+}
+
+// Intersection returns the most general subject that is matched by both subjects.
+// For example the intersection of "a.*.c" and "a.b.*" is "a.b.c", and the intersection
+// of "a.>" and "*.b" is "a.b". An empty subject is returned if the subjects don't intersect.
+func (s Subject) Intersection(other Subject) Subject {
+	if s == "" || other == "" {
+		return ""
+	}
+	myArray := strings.Split(string(s), ".")
+	otherArray := strings.Split(string(other), ".")
+	var tokens []string
+	for i := 0; ; i++ {
+		myEnd, otherEnd := i >= len(myArray), i >= len(otherArray)
+		if myEnd && otherEnd {
+			break
+		}
+		if myEnd || otherEnd {
+			// > needs at least one token to match, so remaining tokens can't be matched
+			return ""
+		}
+		myTok, otherTok := myArray[i], otherArray[i]
+		if myTok == ">" && i == len(myArray)-1 {
+			tokens = append(tokens, otherArray[i:]...)
+			break
+		}
+		if otherTok == ">" && i == len(otherArray)-1 {
+			tokens = append(tokens, myArray[i:]...)
+			break
+		}
+		switch {
+		case myTok == otherTok || otherTok == "*":
+			tokens = append(tokens, myTok)
+		case myTok == "*":
+			tokens = append(tokens, otherTok)
+		default:
+			return ""
+		}
+	}
+	return Subject(strings.Join(tokens, "."))
+}
+
 // TimeRange is used to represent a start and end time
 type TimeRange struct {
 	Start string `json:"start,omitempty"`
@@ -288,6 +451,76 @@ func (u *UserLimits) IsUnlimited() bool {
 	return len(u.Src) == 0 && len(u.Times) == 0
 }
 
+// parseTimeOfDay returns the offset of a "15:04:05" time from midnight
+func parseTimeOfDay(v string) (time.Duration, error) {
+	t, err := time.Parse("15:04:05", v)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute +
+		time.Duration(t.Second())*time.Second, nil
+}
+
+// Contains returns true if the time of day of t is within the range, the start is inclusive
+// and the end exclusive. A range with a start after its end crosses midnight.
+func (tr *TimeRange) Contains(t time.Time) bool {
+	start, err := parseTimeOfDay(tr.Start)
+	if err != nil {
+		return false
+	}
+	end, err := parseTimeOfDay(tr.End)
+	if err != nil {
+		return false
+	}
+	now := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute +
+		time.Duration(t.Second())*time.Second + time.Duration(t.Nanosecond())
+	if start <= end {
+		return now >= start && now < end
+	}
+	return now >= start || now < end
+}
+
+// AllowsAt returns true if t is within one of the time ranges, evaluated in the Locale
+// time zone or the local time zone if Locale is not set. No time ranges allow all times.
+// An invalid Locale or time range doesn't allow any time.
+func (u *UserLimits) AllowsAt(t time.Time) bool {
+	if len(u.Times) == 0 {
+		return true
+	}
+	loc := time.Local
+	if u.Locale != "" {
+		var err error
+		if loc, err = time.LoadLocation(u.Locale); err != nil {
+			return false
+		}
+	}
+	t = t.In(loc)
+	for _, tr := range u.Times {
+		if tr.Contains(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsSource returns true if the ip is contained in one of the Src CIDRs.
+// No Src CIDRs allow all addresses. Invalid CIDRs don't allow any address.
+func (u *UserLimits) AllowsSource(ip net.IP) bool {
+	if len(u.Src) == 0 {
+		return true
+	}
+	if ip == nil {
+		return false
+	}
+	for _, cidr := range u.Src {
+		_, ipNet, err := net.ParseCIDR(strings.TrimSpace(cidr))
+		if err == nil && ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
 // Limits are used to control acccess for users and importing accounts
 type Limits struct {
 	UserLimits
@@ -300,6 +533,7 @@ func (l *Limits) IsUnlimited() bool {
 
 // Validate checks the values in a limit struct
 func (l *Limits) Validate(vr *ValidationResults) {
+	l.NatsLimits.Validate(vr)
 	if len(l.Src) != 0 {
 		for _, cidr := range l.Src {
 			_, ipNet, err := net.ParseCIDR(cidr)
@@ -358,6 +592,93 @@ func (p *Permission) Validate(vr *ValidationResults, permitQueue bool) {
 	}
 }
 
+// Allows returns true if a publish or a plain subscription to the subject is permitted.
+// An empty allow list permits all subjects. Entries with a queue group are ignored.
+func (p *Permission) Allows(subject Subject) bool {
+	return NewPermissionMatcher(p).Allows(subject)
+}
+
+// AllowsQueue returns true if a queue subscription to the subject is permitted.
+func (p *Permission) AllowsQueue(subject Subject, queue string) bool {
+	return NewPermissionMatcher(p).AllowsQueue(subject, queue)
+}
+
+// PermissionMatcher evaluates subjects against the allow and deny lists of a Permission.
+// Build it once with NewPermissionMatcher when evaluating many subjects.
+type PermissionMatcher struct {
+	allow *Sublist
+	deny  *Sublist
+}
+
+// NewPermissionMatcher creates a PermissionMatcher for the provided permission
+func NewPermissionMatcher(p *Permission) *PermissionMatcher {
+	m := &PermissionMatcher{}
+	if len(p.Allow) > 0 {
+		m.allow = permissionSublist(p.Allow)
+	}
+	m.deny = permissionSublist(p.Deny)
+	return m
+}
+
+func permissionSublist(entries StringList) *Sublist {
+	sl := NewSublist()
+	for _, e := range entries {
+		tk := strings.Split(e, " ")
+		queue := ""
+		if len(tk) == 2 {
+			queue = tk[1]
+		}
+		sl.Insert(Subject(tk[0]), queue)
+	}
+	return sl
+}
+
+// matchEntries returns whether entries without a queue group match the subject, whether entries
+// with a queue group match it, and whether the queue is one of the matching queue groups
+func matchEntries(sl *Sublist, subject Subject, queue string) (plain bool, queued bool, queueMatch bool) {
+	for _, v := range sl.Match(subject) {
+		q := v.(string)
+		if q == "" {
+			plain = true
+			continue
+		}
+		queued = true
+		// queue groups can contain wildcards, the literal name is checked first as * and > are valid names
+		if q == queue || (queue != "" && Subject(q).HasWildCards() && Subject(queue).IsContainedIn(Subject(q))) {
+			queueMatch = true
+		}
+	}
+	return plain, queued, queueMatch
+}
+
+// Allows returns true if a publish or a plain subscription to the subject is permitted.
+func (m *PermissionMatcher) Allows(subject Subject) bool {
+	return m.AllowsQueue(subject, "")
+}
+
+// AllowsQueue returns true if a queue subscription to the subject is permitted.
+// An empty queue evaluates a plain subscription, for which entries with a queue group are ignored.
+// As in the server, entries with a queue group matching the subject decide for queue subscriptions,
+// in the allow list as well as in the deny list. Queue groups in entries can use wildcards.
+func (m *PermissionMatcher) AllowsQueue(subject Subject, queue string) bool {
+	allowed := true
+	if m.allow != nil {
+		plain, queued, queueMatch := matchEntries(m.allow, subject, queue)
+		allowed = plain
+		if queue != "" && queued {
+			allowed = queueMatch
+		}
+	}
+	if allowed {
+		plain, queued, queueMatch := matchEntries(m.deny, subject, queue)
+		allowed = !plain
+		if queue != "" && queued {
+			allowed = !queueMatch
+		}
+	}
+	return allowed
+}
+
 // ResponsePermission can be used to allow responses to any reply subject
 // that is received on a valid subscription.
 type ResponsePermission struct {
@@ -365,9 +686,61 @@ type ResponsePermission struct {
 	Expires time.Duration `json:"ttl"`
 }
 
+const (
+	// DefaultResponseMaxMsgs is the number of responses the server allows if MaxMsgs is 0
+	DefaultResponseMaxMsgs = 1
+	// DefaultResponseExpires is the time the server allows responses for if Expires is 0
+	DefaultResponseExpires = 2 * time.Minute
+)
+
 // Validate the response permission.
-func (p *ResponsePermission) Validate(_ *ValidationResults) {
-	// Any values can be valid for now.
+func (p *ResponsePermission) Validate(vr *ValidationResults) {
+	if p.MaxMsgs < 0 {
+		vr.AddError("response permission max messages %d is negative", p.MaxMsgs)
+	}
+	if p.Expires < 0 {
+		vr.AddError("response permission ttl %v is negative", p.Expires)
+	} else if p.Expires > 0 && p.Expires < time.Millisecond {
+		// ttl is in nanoseconds, small values are likely meant as seconds
+		vr.AddWarning("response permission ttl %v is less than a millisecond", p.Expires)
+	}
+}
+
+// EffectiveMaxMsgs returns the number of responses allowed per request, applying the server default
+func (p *ResponsePermission) EffectiveMaxMsgs() int {
+	if p.MaxMsgs == 0 {
+		return DefaultResponseMaxMsgs
+	}
+	return p.MaxMsgs
+}
+
+// EffectiveExpires returns the time responses are allowed for after a request is received,
+// applying the server default
+func (p *ResponsePermission) EffectiveExpires() time.Duration {
+	if p.Expires == 0 {
+		return DefaultResponseExpires
+	}
+	return p.Expires
+}
+
+// WithDefaults returns a copy of the response permission with the server defaults applied
+func (p *ResponsePermission) WithDefaults() ResponsePermission {
+	return ResponsePermission{MaxMsgs: p.EffectiveMaxMsgs(), Expires: p.EffectiveExpires()}
+}
+
+// Allows returns true if another response can be published to the reply subject of a request
+// received elapsed ago, for which sent responses were already published
+func (p *ResponsePermission) Allows(sent int, elapsed time.Duration) bool {
+	return sent < p.EffectiveMaxMsgs() && elapsed <= p.EffectiveExpires()
+}
+
+func (p *ResponsePermission) String() string {
+	n := p.EffectiveMaxMsgs()
+	unit := "responses"
+	if n == 1 {
+		unit = "response"
+	}
+	return fmt.Sprintf("%d %s within %v of a request", n, unit, p.EffectiveExpires())
 }
 
 // Permissions are used to restrict subject access, either on a user or for everyone on a server by default
@@ -377,6 +750,15 @@ type Permissions struct {
 	Resp *ResponsePermission `json:"resp,omitempty"`
 }
 
+// ResponseAllowance describes the responses allowed to reply subjects of received requests,
+// which are allowed regardless of the publish permissions
+func (p *Permissions) ResponseAllowance() string {
+	if p.Resp == nil {
+		return "responses are limited by the publish permissions"
+	}
+	return p.Resp.String()
+}
+
 // Validate the pub and sub fields in the permissions list
 func (p *Permissions) Validate(vr *ValidationResults) {
 	if p.Resp != nil {
@@ -17,10 +17,12 @@ package jwt
 
 import (
 	"crypto/rand"
+	"net"
 	"os"
 	"regexp"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestVersion(t *testing.T) {
@@ -363,6 +365,50 @@ func TestPermissions_Validate(t *testing.T) {
 	AssertTrue(len(vr.Errors()) == 2, t)
 }
 
+func TestRenamingSubject_Transform(t *testing.T) {
+	cases := []struct {
+		from, to       string
+		subject, local string
+		reverseFailure bool
+	}{
+		{"foo.*.*", "bar.$2.$1", "foo.a.b", "bar.b.a", false},
+		{"foo.*.*", "bar.*.*", "foo.a.b", "bar.a.b", false},
+		{"foo.*.>", "bar.$1.>", "foo.a.b.c", "bar.a.b.c", false},
+		{"foo.>", "bar.>", "foo.a", "bar.a", false},
+		{"foo.*.*", "bar.$1.$1.*", "foo.a.b", "bar.a.a.a", true},
+		{"foo", "bar", "foo", "bar", false},
+		{"foo.*", "", "foo.a", "foo.a", false},
+	}
+	for _, c := range cases {
+		rs := RenamingSubject(c.to)
+		local, err := rs.Transform(Subject(c.from), c.subject)
+		AssertNoError(err, t)
+		AssertEquals(c.local, local, t)
+		subject, err := rs.ReverseTransform(Subject(c.from), c.local)
+		if c.reverseFailure {
+			AssertTrue(err != nil, t)
+			continue
+		}
+		AssertNoError(err, t)
+		AssertEquals(c.subject, subject, t)
+	}
+}
+
+func TestRenamingSubject_TransformErrors(t *testing.T) {
+	rs := RenamingSubject("bar.$2")
+	_, err := rs.Transform("foo.*.*", "baz.a.b")
+	AssertTrue(err != nil, t)
+	_, err = rs.Transform("foo.*.*", "foo.*.b")
+	AssertTrue(err != nil, t)
+	_, err = RenamingSubject("bar.$3").Transform("foo.*.*", "foo.a.b")
+	AssertTrue(err != nil, t)
+	// first wildcard is not referenced
+	_, err = rs.ReverseTransform("foo.*.*", "bar.b")
+	AssertTrue(err != nil, t)
+	_, err = RenamingSubject("bar.$1.$1").ReverseTransform("foo.*", "bar.a.b")
+	AssertTrue(err != nil, t)
+}
+
 func TestRenamingSubject_ToSubject(t *testing.T) {
 	AssertEquals(RenamingSubject("foo.$2.$1.bar").ToSubject(), Subject("foo.*.*.bar"), t)
 	AssertEquals(RenamingSubject("foo.*.bar").ToSubject(), Subject("foo.*.bar"), t)
@@ -427,3 +473,184 @@ func TestInvalidInfo(t *testing.T) {
 		}
 	}
 }
+
+func TestPermission_Allows(t *testing.T) {
+	p := Permission{}
+	AssertTrue(p.Allows("anything"), t)
+
+	p.Allow.Add("foo.>", "bar", "q.* grp")
+	p.Deny.Add("foo.secret")
+	AssertTrue(p.Allows("foo.bar"), t)
+	AssertTrue(p.Allows("bar"), t)
+	AssertFalse(p.Allows("foo.secret"), t)
+	AssertFalse(p.Allows("baz"), t)
+	AssertFalse(p.Allows("q.x"), t)
+	AssertTrue(p.AllowsQueue("q.x", "grp"), t)
+	AssertFalse(p.AllowsQueue("q.x", "other"), t)
+	AssertTrue(p.AllowsQueue("foo.x", "other"), t)
+	AssertFalse(p.AllowsQueue("foo.secret", "other"), t)
+}
+
+func TestPermission_AllowsQueuePrecedence(t *testing.T) {
+	// matching queue entries decide for queue subscriptions
+	p := Permission{}
+	p.Allow.Add("foo", "foo q1")
+	AssertTrue(p.Allows("foo"), t)
+	AssertTrue(p.AllowsQueue("foo", "q1"), t)
+	AssertFalse(p.AllowsQueue("foo", "q2"), t)
+
+	p = Permission{}
+	p.Deny.Add("foo", "foo q1")
+	AssertFalse(p.Allows("foo"), t)
+	AssertFalse(p.AllowsQueue("foo", "q1"), t)
+	AssertTrue(p.AllowsQueue("foo", "q2"), t)
+
+	p = Permission{}
+	p.Deny.Add("foo")
+	AssertFalse(p.AllowsQueue("foo", "q1"), t)
+}
+
+func TestPermission_AllowsQueueWildcard(t *testing.T) {
+	p := Permission{}
+	p.Allow.Add("foo q.*", "bar *")
+	AssertTrue(p.AllowsQueue("foo", "q.a"), t)
+	AssertFalse(p.AllowsQueue("foo", "q.a.b"), t)
+	AssertFalse(p.AllowsQueue("foo", "r.a"), t)
+	AssertTrue(p.AllowsQueue("bar", "*"), t)
+	AssertTrue(p.AllowsQueue("bar", "any"), t)
+
+	p = Permission{}
+	p.Deny.Add("foo q.>")
+	AssertFalse(p.AllowsQueue("foo", "q.a.b"), t)
+	AssertTrue(p.AllowsQueue("foo", "r"), t)
+	AssertTrue(p.Allows("foo"), t)
+}
+
+func TestSubjectIntersection(t *testing.T) {
+	cases := []struct {
+		a, b     Subject
+		expected Subject
+	}{
+		{"a.*.c", "a.b.*", "a.b.c"},
+		{"a.b", "a.b", "a.b"},
+		{"a.b", "a.c", ""},
+		{"a.>", "a.*", "a.*"},
+		{"a.>", "*.b.>", "a.b.>"},
+		{">", "foo.bar", "foo.bar"},
+		{">", ">", ">"},
+		{"a.>", "a", ""},
+		{"a.*", "a.b.c", ""},
+		{"*.*", "a.>", "a.*"},
+		{"", "a", ""},
+	}
+	for _, c := range cases {
+		AssertEquals(c.expected, c.a.Intersection(c.b), t)
+		AssertEquals(c.expected, c.b.Intersection(c.a), t)
+		AssertEquals(c.expected != "", c.a.Intersects(c.b), t)
+	}
+}
+
+func TestUserLimits_AllowsAt(t *testing.T) {
+	u := UserLimits{}
+	AssertTrue(u.AllowsAt(time.Now()), t)
+
+	u.Locale = "America/New_York"
+	u.Times = []TimeRange{{Start: "09:00:00", End: "17:00:00"}}
+	loc, err := time.LoadLocation(u.Locale)
+	AssertNoError(err, t)
+	AssertTrue(u.AllowsAt(time.Date(2026, 1, 5, 9, 0, 0, 0, loc)), t)
+	AssertTrue(u.AllowsAt(time.Date(2026, 1, 5, 16, 59, 59, 0, loc)), t)
+	AssertFalse(u.AllowsAt(time.Date(2026, 1, 5, 17, 0, 0, 0, loc)), t)
+	AssertFalse(u.AllowsAt(time.Date(2026, 1, 5, 8, 59, 59, 0, loc)), t)
+	// the time is evaluated in the locale, 14:00 UTC is 09:00 in New York in winter
+	AssertTrue(u.AllowsAt(time.Date(2026, 1, 5, 14, 0, 0, 0, time.UTC)), t)
+	AssertFalse(u.AllowsAt(time.Date(2026, 1, 5, 13, 0, 0, 0, time.UTC)), t)
+
+	// ranges crossing midnight
+	u.Locale = "UTC"
+	u.Times = []TimeRange{{Start: "22:00:00", End: "02:00:00"}}
+	AssertTrue(u.AllowsAt(time.Date(2026, 1, 5, 23, 0, 0, 0, time.UTC)), t)
+	AssertTrue(u.AllowsAt(time.Date(2026, 1, 6, 0, 30, 0, 0, time.UTC)), t)
+	AssertFalse(u.AllowsAt(time.Date(2026, 1, 6, 2, 0, 0, 0, time.UTC)), t)
+	AssertFalse(u.AllowsAt(time.Date(2026, 1, 6, 12, 0, 0, 0, time.UTC)), t)
+
+	// any matching range allows
+	u.Times = append(u.Times, TimeRange{Start: "11:00:00", End: "13:00:00"})
+	AssertTrue(u.AllowsAt(time.Date(2026, 1, 6, 12, 0, 0, 0, time.UTC)), t)
+
+	u.Locale = "Not/AZone"
+	AssertFalse(u.AllowsAt(time.Date(2026, 1, 6, 12, 0, 0, 0, time.UTC)), t)
+	u.Locale = ""
+	u.Times = []TimeRange{{Start: "bad", End: "13:00:00"}}
+	AssertFalse(u.AllowsAt(time.Now()), t)
+}
+
+func TestUserLimits_AllowsSource(t *testing.T) {
+	u := UserLimits{}
+	AssertTrue(u.AllowsSource(net.ParseIP("192.168.1.1")), t)
+
+	u.Src.Set("192.168.0.0/16,2001:db8::/32")
+	AssertTrue(u.AllowsSource(net.ParseIP("192.168.1.1")), t)
+	AssertTrue(u.AllowsSource(net.ParseIP("::ffff:192.168.1.1")), t)
+	AssertFalse(u.AllowsSource(net.ParseIP("10.0.0.1")), t)
+	AssertTrue(u.AllowsSource(net.ParseIP("2001:db8::1")), t)
+	AssertFalse(u.AllowsSource(net.ParseIP("2001:db9::1")), t)
+	AssertFalse(u.AllowsSource(nil), t)
+
+	u.Src = CIDRList{"not-a-cidr"}
+	AssertFalse(u.AllowsSource(net.ParseIP("10.0.0.1")), t)
+}
+
+func TestResponsePermission_Validate(t *testing.T) {
+	vr := CreateValidationResults()
+	(&ResponsePermission{}).Validate(vr)
+	AssertTrue(vr.IsEmpty(), t)
+
+	vr = CreateValidationResults()
+	(&ResponsePermission{MaxMsgs: -1, Expires: -time.Second}).Validate(vr)
+	AssertEquals(2, len(vr.Errors()), t)
+
+	vr = CreateValidationResults()
+	(&ResponsePermission{MaxMsgs: 1, Expires: 5}).Validate(vr)
+	AssertFalse(vr.IsBlocking(true), t)
+	AssertEquals(1, len(vr.Warnings()), t)
+}
+
+func TestResponsePermission_Defaults(t *testing.T) {
+	p := &ResponsePermission{}
+	AssertEquals(DefaultResponseMaxMsgs, p.EffectiveMaxMsgs(), t)
+	AssertEquals(DefaultResponseExpires, p.EffectiveExpires(), t)
+	AssertEquals(ResponsePermission{MaxMsgs: 1, Expires: 2 * time.Minute}, p.WithDefaults(), t)
+	AssertEquals("1 response within 2m0s of a request", p.String(), t)
+
+	AssertTrue(p.Allows(0, time.Minute), t)
+	AssertFalse(p.Allows(1, time.Minute), t)
+	AssertFalse(p.Allows(0, 3*time.Minute), t)
+
+	p = &ResponsePermission{MaxMsgs: 5, Expires: time.Second}
+	AssertTrue(p.Allows(4, time.Second), t)
+	AssertFalse(p.Allows(5, 0), t)
+	AssertEquals("5 responses within 1s of a request", p.String(), t)
+
+	perms := Permissions{}
+	AssertEquals("responses are limited by the publish permissions", perms.ResponseAllowance(), t)
+	perms.Resp = p
+	AssertEquals(p.String(), perms.ResponseAllowance(), t)
+}
+
+func TestResponsePermission_AppliesToTemplates(t *testing.T) {
+	account := NewAccountClaims(publicKey(createAccountNKey(t), t))
+	account.DefaultPermissions.Resp = &ResponsePermission{MaxMsgs: -1}
+	vr := CreateValidationResults()
+	account.Validate(vr)
+	AssertTrue(vr.IsBlocking(false), t)
+
+	account.DefaultPermissions.Resp = nil
+	scope := NewUserScope()
+	scope.Key = publicKey(createAccountNKey(t), t)
+	scope.Template.Resp = &ResponsePermission{Expires: -time.Second}
+	account.SigningKeys.AddScopedSigner(scope)
+	vr = CreateValidationResults()
+	account.Validate(vr)
+	AssertTrue(vr.IsBlocking(false), t)
+}
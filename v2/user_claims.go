@@ -18,6 +18,7 @@ package jwt
 import (
 	"errors"
 	"reflect"
+	"strings"
 
 	"github.com/nats-io/nkeys"
 )
@@ -32,6 +33,16 @@ const (
 	ConnectionTypeInProcess  = "IN_PROCESS"
 )
 
+// IsKnownConnectionType returns true if the connection type is one of the ConnectionType constants
+func IsKnownConnectionType(ct string) bool {
+	switch ct {
+	case ConnectionTypeStandard, ConnectionTypeWebsocket, ConnectionTypeLeafnode, ConnectionTypeLeafnodeWS,
+		ConnectionTypeMqtt, ConnectionTypeMqttWS, ConnectionTypeInProcess:
+		return true
+	}
+	return false
+}
+
 type UserPermissionLimits struct {
 	Permissions
 	Limits
@@ -40,6 +51,72 @@ type UserPermissionLimits struct {
 	AllowedConnectionTypes StringList `json:"allowed_connection_types,omitempty"`
 }
 
+// AllowsConnectionType returns true if a connection of the type is allowed.
+// No allowed connection types allow all connection types.
+func (u *UserPermissionLimits) AllowsConnectionType(ct string) bool {
+	if len(u.AllowedConnectionTypes) == 0 {
+		return true
+	}
+	for _, v := range u.AllowedConnectionTypes {
+		if strings.EqualFold(v, ct) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateConnectionTypes warns about unknown connection types, these are
+// ignored by servers not supporting them
+func (u *UserPermissionLimits) validateConnectionTypes(vr *ValidationResults) {
+	known := 0
+	for _, ct := range u.AllowedConnectionTypes {
+		if IsKnownConnectionType(ct) {
+			known++
+		} else if up := strings.ToUpper(ct); IsKnownConnectionType(up) {
+			vr.AddWarning("connection type %q should be %q", ct, up)
+			known++
+		} else {
+			vr.AddWarning("unknown connection type %q", ct)
+		}
+	}
+	if len(u.AllowedConnectionTypes) > 0 && known == 0 {
+		vr.AddWarning("none of the allowed connection types are known, the user may not be able to connect")
+	}
+}
+
+// ValidateConnectionTypes checks the allowed connection types against the limits of the
+// account and the permissions of the user
+func (u *UserPermissionLimits) ValidateConnectionTypes(account *AccountClaims, vr *ValidationResults) {
+	leaf := u.allowsAnyConnectionType(ConnectionTypeLeafnode, ConnectionTypeLeafnodeWS)
+	mqtt := u.allowsAnyConnectionType(ConnectionTypeMqtt, ConnectionTypeMqttWS)
+	if account != nil && leaf && account.Limits.LeafNodeConn == 0 {
+		vr.AddWarning("user allows leaf node connections but account %q does not allow any", account.Subject)
+	}
+	if mqtt {
+		for _, subj := range append(append(StringList{}, u.Sub.Allow...), u.Sub.Deny...) {
+			if strings.Contains(subj, " ") {
+				vr.AddWarning("user allows MQTT connections which don't use queue groups, but has queue permission %q", subj)
+			}
+		}
+		if account != nil && !account.Limits.IsJSEnabled() {
+			vr.AddWarning("user allows MQTT connections but account %q does not enable JetStream", account.Subject)
+		}
+	}
+}
+
+// allowsAnyConnectionType returns true if one of the types is explicitly allowed
+func (u *UserPermissionLimits) allowsAnyConnectionType(types ...string) bool {
+	if len(u.AllowedConnectionTypes) == 0 {
+		return false
+	}
+	for _, ct := range types {
+		if u.AllowsConnectionType(ct) {
+			return true
+		}
+	}
+	return false
+}
+
 // User defines the user specific data in a user JWT
 type User struct {
 	UserPermissionLimits
@@ -53,9 +130,40 @@ type User struct {
 func (u *User) Validate(vr *ValidationResults) {
 	u.Permissions.Validate(vr)
 	u.Limits.Validate(vr)
+	u.validateConnectionTypes(vr)
 	// When BearerToken is true server will ignore any nonce-signing verification
 }
 
+// ValidateLimitsAgainst warns about nats limits of the user exceeding the limits of the account,
+// the server enforces the lower value. Users issued by a scoped signing key are checked with the
+// limits of the scope.
+func (u *UserClaims) ValidateLimitsAgainst(account *AccountClaims, vr *ValidationResults) {
+	if account == nil {
+		return
+	}
+	limits := u.Limits.NatsLimits
+	if scope := userScope(account, u); scope != nil {
+		limits = scope.Template.NatsLimits
+	}
+	check := func(name string, user int64, acc int64) {
+		// the server doesn't limit users with a limit of 0
+		if user == 0 {
+			user = NoLimit
+		}
+		if acc == NoLimit || (user != NoLimit && user <= acc) {
+			return
+		}
+		if user == NoLimit {
+			vr.AddWarning("user %s limit is unlimited but account %q limits it to %d", name, account.Subject, acc)
+		} else {
+			vr.AddWarning("user %s limit %d exceeds the limit %d of account %q", name, user, acc, account.Subject)
+		}
+	}
+	check("subscriptions", limits.Subs, account.Limits.Subs)
+	check("data", limits.Data, account.Limits.Data)
+	check("payload", limits.Payload, account.Limits.Payload)
+}
+
 // UserClaims defines a user JWT
 type UserClaims struct {
 	ClaimsData
@@ -478,3 +478,60 @@ func TestUserClaimsSignerFn(t *testing.T) {
 		t.Fatalf("claims validation should not have failed, got %+v", vr.Issues)
 	}
 }
+
+func TestUserConnectionTypes_Validate(t *testing.T) {
+	uc := NewUserClaims(publicKey(createUserNKey(t), t))
+	AssertTrue(uc.AllowsConnectionType(ConnectionTypeMqtt), t)
+
+	uc.AllowedConnectionTypes.Add(ConnectionTypeStandard, "websocket", "CARRIER_PIGEON")
+	vr := CreateValidationResults()
+	uc.Validate(vr)
+	AssertFalse(vr.IsBlocking(true), t)
+	AssertTrue(hasIssue(vr, false, `connection type "websocket" should be "WEBSOCKET"`), t)
+	AssertTrue(hasIssue(vr, false, `unknown connection type "CARRIER_PIGEON"`), t)
+	AssertFalse(hasIssue(vr, false, "none of the allowed connection types"), t)
+
+	AssertTrue(uc.AllowsConnectionType(ConnectionTypeStandard), t)
+	AssertTrue(uc.AllowsConnectionType(ConnectionTypeWebsocket), t)
+	AssertFalse(uc.AllowsConnectionType(ConnectionTypeMqtt), t)
+
+	uc.AllowedConnectionTypes = StringList{"STANDRAD"}
+	vr = CreateValidationResults()
+	uc.Validate(vr)
+	AssertTrue(hasIssue(vr, false, "none of the allowed connection types"), t)
+	AssertFalse(uc.AllowsConnectionType(ConnectionTypeStandard), t)
+}
+
+func TestUserConnectionTypes_CrossChecks(t *testing.T) {
+	account := NewAccountClaims(publicKey(createAccountNKey(t), t))
+	uc := NewUserClaims(publicKey(createUserNKey(t), t))
+	uc.Sub.Allow.Add("q.* workers")
+
+	// no explicit connection types don't trigger cross checks
+	account.Limits.LeafNodeConn = 0
+	vr := CreateValidationResults()
+	uc.ValidateConnectionTypes(account, vr)
+	AssertTrue(vr.IsEmpty(), t)
+
+	uc.AllowedConnectionTypes.Add(ConnectionTypeLeafnode)
+	vr = CreateValidationResults()
+	uc.ValidateConnectionTypes(account, vr)
+	AssertTrue(hasIssue(vr, false, "allows leaf node connections"), t)
+	account.Limits.LeafNodeConn = NoLimit
+	vr = CreateValidationResults()
+	uc.ValidateConnectionTypes(account, vr)
+	AssertTrue(vr.IsEmpty(), t)
+
+	uc.AllowedConnectionTypes.Add(ConnectionTypeMqttWS)
+	vr = CreateValidationResults()
+	uc.ValidateConnectionTypes(account, vr)
+	AssertTrue(hasIssue(vr, false, `has queue permission "q.* workers"`), t)
+	AssertTrue(hasIssue(vr, false, "does not enable JetStream"), t)
+
+	uc.Sub.Allow = nil
+	account.Limits.JetStreamLimits.DiskStorage = NoLimit
+	vr = CreateValidationResults()
+	uc.ValidateConnectionTypes(account, vr)
+	AssertTrue(vr.IsEmpty(), t)
+	AssertFalse(vr.IsBlocking(true), t)
+}